@@ -0,0 +1,373 @@
+package goform
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"image"
+	"image/color"
+	"image/png"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrCaptchaMisconfigured = errors.New("Captcha provider is not configured")
+)
+
+// CaptchaProvider generates captcha challenges and verifies the answers
+// submitted for them.
+type CaptchaProvider interface {
+	Challenge(name string) (*CaptchaChallenge, error)
+	Verify(name string, response CaptchaResponse) (bool, error)
+}
+
+// CaptchaChallenge is what a theme renders for a captcha element. Widget is
+// the markup of the provider, Hidden are the hidden fields carrying the state
+// of the challenge and Answer tells the theme to render a text input, named
+// after the element, for the answer.
+type CaptchaChallenge struct {
+	Widget template.HTML
+	Hidden []*Attribute
+	Answer bool
+}
+
+// CaptchaResponse is the part of a request a provider verifies.
+type CaptchaResponse struct {
+	Values   url.Values
+	RemoteIp string
+}
+
+// ArithmeticCaptcha asks for the result of a small sum. The answer is kept in
+// a signed, expiring token in a hidden field, so no server side storage is
+// needed. With Image the question is drawn as an image instead of text.
+//
+// There are only 19 possible answers and without Nonces a token can be
+// submitted again until it expires, also after it was solved.
+// NewArithmeticCaptcha sets a MemoryNonceStore so each token is verified once,
+// a shared store is needed when several processes verify the form. Rate limit
+// the form against guessing.
+type ArithmeticCaptcha struct {
+	Secret []byte
+	TTL    time.Duration
+	Image  bool
+	Nonces NonceStore
+}
+
+// NonceStore remembers the tokens of verified captchas. Use marks a nonce as
+// used until it expires and reports whether it was unused before.
+type NonceStore interface {
+	Use(nonce string, expires time.Time) bool
+}
+
+// MemoryNonceStore is a NonceStore of a single process, nonces are forgotten
+// once they expire.
+type MemoryNonceStore struct {
+	mu     sync.Mutex
+	nonces map[string]time.Time
+	pruned time.Time
+}
+
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{nonces: map[string]time.Time{}}
+}
+
+func (store *MemoryNonceStore) Use(nonce string, expires time.Time) bool {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	now := time.Now()
+	if now.Sub(store.pruned) > time.Minute {
+		for n, e := range store.nonces {
+			if now.After(e) {
+				delete(store.nonces, n)
+			}
+		}
+		store.pruned = now
+	}
+	if e, ok := store.nonces[nonce]; ok && !now.After(e) {
+		return false
+	}
+	if store.nonces == nil {
+		store.nonces = map[string]time.Time{}
+	}
+	store.nonces[nonce] = expires
+	return true
+}
+
+func NewArithmeticCaptcha(secret []byte, image bool) *ArithmeticCaptcha {
+	return &ArithmeticCaptcha{
+		Secret: secret,
+		TTL:    10 * time.Minute,
+		Image:  image,
+		Nonces: NewMemoryNonceStore(),
+	}
+}
+
+func (captcha *ArithmeticCaptcha) Challenge(name string) (*CaptchaChallenge, error) {
+	if len(captcha.Secret) == 0 {
+		return nil, ErrCaptchaMisconfigured
+	}
+	a, err := randomInt(10)
+	if err != nil {
+		return nil, err
+	}
+	b, err := randomInt(10)
+	if err != nil {
+		return nil, err
+	}
+	operator, answer := "+", a+b
+	if a >= b {
+		if minus, err := randomInt(2); err != nil {
+			return nil, err
+		} else if minus == 1 {
+			operator, answer = "-", a-b
+		}
+	}
+	question := strconv.Itoa(a) + " " + operator + " " + strconv.Itoa(b) + " = ?"
+
+	token, err := captcha.sign(strconv.Itoa(answer), time.Now().Add(captcha.TTL))
+	if err != nil {
+		return nil, err
+	}
+
+	var widget template.HTML
+	if captcha.Image {
+		src, err := arithmeticImage(strings.Replace(question, " ", "", -1))
+		if err != nil {
+			return nil, err
+		}
+		widget = template.HTML(`<img src="` + template.HTMLEscapeString(src) + `" alt="captcha" />`)
+	} else {
+		widget = template.HTML(`<span class="captcha-question">` + template.HTMLEscapeString(question) + `</span>`)
+	}
+
+	return &CaptchaChallenge{
+		Widget: widget,
+		Hidden: []*Attribute{{Key: name + "_token", Value: token}},
+		Answer: true,
+	}, nil
+}
+
+func (captcha *ArithmeticCaptcha) Verify(name string, response CaptchaResponse) (bool, error) {
+	if len(captcha.Secret) == 0 {
+		return false, ErrCaptchaMisconfigured
+	}
+	answer := strings.TrimSpace(response.Values.Get(name))
+	token := response.Values.Get(name + "_token")
+	parts := strings.SplitN(token, ".", 3)
+	if answer == "" || len(parts) != 3 {
+		return false, nil
+	}
+	// tokens expiring later than a new one would are forged, they are not
+	// stored as used
+	now := time.Now()
+	expires, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || now.Unix() > expires || expires > now.Add(captcha.TTL).Unix() {
+		return false, nil
+	}
+	// a token is used by any answer, so it cannot be tried with every answer
+	if captcha.Nonces != nil && !captcha.Nonces.Use(parts[1], time.Unix(expires, 0)) {
+		return false, nil
+	}
+	expected := captcha.mac(parts[0], parts[1], answer)
+	return hmac.Equal([]byte(parts[2]), []byte(expected)), nil
+}
+
+// sign returns "expires.nonce.mac", the mac covers the answer which is not
+// part of the token itself.
+func (captcha *ArithmeticCaptcha) sign(answer string, expires time.Time) (string, error) {
+	nonce := make([]byte, 12)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	expiresStr := strconv.FormatInt(expires.Unix(), 10)
+	nonceStr := base64.RawURLEncoding.EncodeToString(nonce)
+	return expiresStr + "." + nonceStr + "." + captcha.mac(expiresStr, nonceStr, answer), nil
+}
+
+func (captcha *ArithmeticCaptcha) mac(expires string, nonce string, answer string) string {
+	h := hmac.New(sha256.New, captcha.Secret)
+	h.Write([]byte(expires + "." + nonce + "." + answer))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func randomInt(max int64) (int, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(max))
+	if err != nil {
+		return 0, err
+	}
+	return int(n.Int64()), nil
+}
+
+// captchaGlyphs are 3x5 bitmaps of the characters of an arithmetic question.
+var captchaGlyphs = map[rune][5]string{
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"###", "..#", "###", "#..", "###"},
+	'3': {"###", "..#", "###", "..#", "###"},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "###", "..#", "###"},
+	'6': {"###", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", ".#.", ".#.", ".#."},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "###"},
+	'+': {"...", ".#.", "###", ".#.", "..."},
+	'-': {"...", "...", "###", "...", "..."},
+	'=': {"...", "###", "...", "###", "..."},
+	'?': {"###", "..#", ".##", "...", ".#."},
+}
+
+// arithmeticImage draws the question with jittered glyphs and noise and
+// returns it as a PNG data URL.
+func arithmeticImage(question string) (string, error) {
+	const scale, padding = 6, 10
+	width := padding*2 + len(question)*4*scale
+	height := padding*2 + 5*scale
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	background := color.RGBA{R: 245, G: 245, B: 245, A: 255}
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, background)
+		}
+	}
+
+	for i, r := range question {
+		glyph, ok := captchaGlyphs[r]
+		if !ok {
+			continue
+		}
+		jitter, err := randomInt(padding)
+		if err != nil {
+			return "", err
+		}
+		shade, err := randomInt(100)
+		if err != nil {
+			return "", err
+		}
+		ink := color.RGBA{R: uint8(shade), G: uint8(shade / 2), B: 80, A: 255}
+		left := padding + i*4*scale
+		top := padding/2 + jitter
+		for row, line := range glyph {
+			for col, pixel := range line {
+				if pixel != '#' {
+					continue
+				}
+				for dx := 0; dx < scale; dx++ {
+					for dy := 0; dy < scale; dy++ {
+						img.Set(left+col*scale+dx, top+row*scale+dy, ink)
+					}
+				}
+			}
+		}
+	}
+
+	for i := 0; i < width*height/12; i++ {
+		x, err := randomInt(int64(width))
+		if err != nil {
+			return "", err
+		}
+		y, err := randomInt(int64(height))
+		if err != nil {
+			return "", err
+		}
+		img.Set(x, y, color.RGBA{R: 120, G: 120, B: 120, A: 255})
+	}
+
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buffer.Bytes()), nil
+}
+
+// SiteVerifyCaptcha verifies a widget based captcha with a siteverify style
+// HTTP endpoint, as used by reCAPTCHA and hCaptcha. The widget posts its token
+// in ResponseField, which is sent to VerifyUrl together with the Secret.
+type SiteVerifyCaptcha struct {
+	SiteKey       string
+	Secret        string
+	VerifyUrl     string
+	ScriptUrl     string
+	WidgetClass   string
+	ResponseField string
+	Client        *http.Client
+}
+
+func NewReCaptcha(siteKey string, secret string) *SiteVerifyCaptcha {
+	return &SiteVerifyCaptcha{
+		SiteKey:       siteKey,
+		Secret:        secret,
+		VerifyUrl:     "https://www.google.com/recaptcha/api/siteverify",
+		ScriptUrl:     "https://www.google.com/recaptcha/api.js",
+		WidgetClass:   "g-recaptcha",
+		ResponseField: "g-recaptcha-response",
+	}
+}
+
+func NewHCaptcha(siteKey string, secret string) *SiteVerifyCaptcha {
+	return &SiteVerifyCaptcha{
+		SiteKey:       siteKey,
+		Secret:        secret,
+		VerifyUrl:     "https://api.hcaptcha.com/siteverify",
+		ScriptUrl:     "https://js.hcaptcha.com/1/api.js",
+		WidgetClass:   "h-captcha",
+		ResponseField: "h-captcha-response",
+	}
+}
+
+func (captcha *SiteVerifyCaptcha) Challenge(name string) (*CaptchaChallenge, error) {
+	if captcha.SiteKey == "" {
+		return nil, ErrCaptchaMisconfigured
+	}
+	widget := `<div class="` + template.HTMLEscapeString(captcha.WidgetClass) + `" data-sitekey="` + template.HTMLEscapeString(captcha.SiteKey) + `"></div>`
+	if captcha.ScriptUrl != "" {
+		widget = `<script src="` + template.HTMLEscapeString(sanitizeUrl(captcha.ScriptUrl)) + `" async defer></script>` + widget
+	}
+	return &CaptchaChallenge{Widget: template.HTML(widget)}, nil
+}
+
+func (captcha *SiteVerifyCaptcha) Verify(name string, response CaptchaResponse) (bool, error) {
+	if captcha.Secret == "" || captcha.VerifyUrl == "" {
+		return false, ErrCaptchaMisconfigured
+	}
+	token := response.Values.Get(captcha.ResponseField)
+	if token == "" {
+		return false, nil
+	}
+
+	values := url.Values{"secret": {captcha.Secret}, "response": {token}}
+	if response.RemoteIp != "" {
+		values.Set("remoteip", response.RemoteIp)
+	}
+	client := captcha.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.PostForm(captcha.VerifyUrl, values)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, errors.New("Captcha verification failed with status " + resp.Status)
+	}
+
+	var result struct {
+		Success bool `json:"success"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, err
+	}
+	return result.Success, nil
+}
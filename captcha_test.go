@@ -0,0 +1,178 @@
+package goform
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestArithmeticCaptchaChallenge(t *testing.T) {
+	captcha := NewArithmeticCaptcha([]byte("secret"), true)
+	challenge, err := captcha.Challenge("captcha")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(challenge.Widget), `alt="captcha"`) || strings.Contains(string(challenge.Widget), "?") {
+		t.Errorf("widget gives the question away: %s", challenge.Widget)
+	}
+}
+
+func TestArithmeticCaptchaVerify(t *testing.T) {
+	tests := []struct {
+		name    string
+		nonces  NonceStore
+		answers []string
+		want    []bool
+	}{
+		{"replayed without store", nil, []string{"7", "7"}, []bool{true, true}},
+		{"replayed with store", NewMemoryNonceStore(), []string{"7", "7"}, []bool{true, false}},
+		{"guessed with store", NewMemoryNonceStore(), []string{"6", "7"}, []bool{false, false}},
+	}
+	for _, test := range tests {
+		captcha := NewArithmeticCaptcha([]byte("secret"), false)
+		captcha.Nonces = test.nonces
+		token, err := captcha.sign("7", time.Now().Add(time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, answer := range test.answers {
+			ok, err := captcha.Verify("captcha", CaptchaResponse{Values: url.Values{"captcha": {answer}, "captcha_token": {token}}})
+			if err != nil || ok != test.want[i] {
+				t.Errorf("%s: Verify(%s) = %v, %v, want %v", test.name, answer, ok, err, test.want[i])
+			}
+		}
+	}
+}
+
+func TestArithmeticCaptchaExpired(t *testing.T) {
+	captcha := NewArithmeticCaptcha([]byte("secret"), false)
+	token, _ := captcha.sign("7", time.Now().Add(-time.Second))
+	if ok, _ := captcha.Verify("captcha", CaptchaResponse{Values: url.Values{"captcha": {"7"}, "captcha_token": {token}}}); ok {
+		t.Error("Verify() = true for an expired token")
+	}
+}
+
+func TestArithmeticCaptchaReplayedByDefault(t *testing.T) {
+	captcha := NewArithmeticCaptcha([]byte("secret"), false)
+	token, _ := captcha.sign("7", time.Now().Add(time.Minute))
+	response := CaptchaResponse{Values: url.Values{"captcha": {"7"}, "captcha_token": {token}}}
+	if ok, _ := captcha.Verify("captcha", response); !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+	if ok, _ := captcha.Verify("captcha", response); ok {
+		t.Error("Verify() = true for a replayed token")
+	}
+}
+
+func TestArithmeticCaptchaForgedExpiry(t *testing.T) {
+	nonces := NewMemoryNonceStore()
+	captcha := NewArithmeticCaptcha([]byte("secret"), false)
+	captcha.Nonces = nonces
+	for i := 0; i < 1000; i++ {
+		token := "99999999999." + strconv.Itoa(i) + ".x"
+		if ok, _ := captcha.Verify("captcha", CaptchaResponse{Values: url.Values{"captcha": {"7"}, "captcha_token": {token}}}); ok {
+			t.Fatal("Verify() = true for a forged token")
+		}
+	}
+	if len(nonces.nonces) != 0 {
+		t.Errorf("stored %d nonces of forged tokens", len(nonces.nonces))
+	}
+
+	// tokens signed with a longer TTL than the captcha has are rejected too
+	token, _ := captcha.sign("7", time.Now().Add(captcha.TTL+time.Hour))
+	if ok, _ := captcha.Verify("captcha", CaptchaResponse{Values: url.Values{"captcha": {"7"}, "captcha_token": {token}}}); ok {
+		t.Error("Verify() = true for a token expiring after the TTL")
+	}
+}
+
+func TestBindCaptchasNested(t *testing.T) {
+	captcha := NewArithmeticCaptcha([]byte("secret"), false)
+	token, _ := captcha.sign("7", time.Now().Add(time.Minute))
+	rowToken, _ := captcha.sign("3", time.Now().Add(time.Minute))
+
+	contact := NewGoForm()
+	contact.Add(NewCaptchaElement("captcha", "Captcha", nil, captcha))
+	form := NewGoForm()
+	form.Add(NewSubFormElement("contact", "Contact", nil, contact))
+	form.Add(NewCollectionElement("items", "Items", nil, func() []ElementInterface {
+		return []ElementInterface{NewCaptchaElement("captcha", "Captcha", nil, captcha)}
+	}, 0, 0))
+
+	val := url.Values{
+		"contact[captcha]":        {"7"},
+		"contact[captcha]_token":  {token},
+		"items[0][captcha]":       {"3"},
+		"items[0][captcha]_token": {rowToken},
+	}
+	form.bindValues(val)
+	form.bindCaptchas(val, "192.0.2.1:1234")
+	if !form.IsValid() {
+		t.Errorf("IsValid() = false, want true: %v", form.GetErrorMessages())
+	}
+	items, _ := form.Get("items")
+	row, _ := items.(*CollectionElement).Rows[0].Get("captcha")
+	if ip := row.(*CaptchaElement).GetResponse().RemoteIp; ip != "192.0.2.1" {
+		t.Errorf("RemoteIp = %q, want %q", ip, "192.0.2.1")
+	}
+}
+
+func TestSiteVerifyCaptcha(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    bool
+		wantErr bool
+	}{
+		{"success", func(w http.ResponseWriter, r *http.Request) {
+			if r.PostFormValue("secret") != "secret" || r.PostFormValue("response") != "token" || r.PostFormValue("remoteip") != "10.0.0.1" {
+				t.Errorf("posted %v", r.PostForm)
+			}
+			w.Write([]byte(`{"success":true}`))
+		}, true, false},
+		{"failure", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"success":false,"error-codes":["invalid-input-response"]}`))
+		}, false, false},
+		{"server error", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}, false, true},
+		{"invalid response", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`<html>`))
+		}, false, true},
+	}
+	for _, test := range tests {
+		server := httptest.NewServer(test.handler)
+		captcha := NewReCaptcha("site", "secret")
+		captcha.VerifyUrl = server.URL
+		ok, err := captcha.Verify("captcha", CaptchaResponse{
+			Values:   url.Values{"g-recaptcha-response": {"token"}},
+			RemoteIp: "10.0.0.1",
+		})
+		if ok != test.want || (err != nil) != test.wantErr {
+			t.Errorf("%s: Verify() = %v, %v, want %v, error %v", test.name, ok, err, test.want, test.wantErr)
+		}
+		server.Close()
+	}
+}
+
+func TestSiteVerifyCaptchaNetworkError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	captcha := NewHCaptcha("site", "secret")
+	captcha.VerifyUrl = server.URL
+	ok, err := captcha.Verify("captcha", CaptchaResponse{Values: url.Values{"h-captcha-response": {"token"}}})
+	if ok || err == nil {
+		t.Errorf("Verify() = %v, %v, want false and an error", ok, err)
+	}
+}
+
+func TestSiteVerifyCaptchaNoToken(t *testing.T) {
+	captcha := NewReCaptcha("site", "secret")
+	captcha.VerifyUrl = "http://127.0.0.1:0"
+	if ok, err := captcha.Verify("captcha", CaptchaResponse{Values: url.Values{}}); ok || err != nil {
+		t.Errorf("Verify() = %v, %v, want false without a request", ok, err)
+	}
+}
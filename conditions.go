@@ -0,0 +1,220 @@
+package goform
+
+import (
+	"encoding/json"
+	"html/template"
+	"io"
+	"strings"
+)
+
+type ConditionOperator string
+
+const (
+	ConditionEquals    ConditionOperator = "equals"
+	ConditionNotEquals ConditionOperator = "not-equals"
+	ConditionEmpty     ConditionOperator = "empty"
+	ConditionNotEmpty  ConditionOperator = "not-empty"
+)
+
+// Condition makes an element depend on the value of another element of the
+// same form. ConditionEquals holds when the element has one of Values,
+// ConditionNotEquals when it has none of them. An element is active when all
+// of its conditions hold, inactive elements are not validated, not mapped by
+// MapTo and hidden in the browser by ConditionScript.
+type Condition struct {
+	Element  string            `json:"element"`
+	Operator ConditionOperator `json:"operator"`
+	Values   []string          `json:"values,omitempty"`
+}
+
+func (condition *Condition) matches(values []string) bool {
+	switch condition.Operator {
+	case ConditionEmpty:
+		return len(values) == 0
+	case ConditionNotEmpty:
+		return len(values) > 0
+	case ConditionNotEquals:
+		return !containsAny(values, condition.Values)
+	default:
+		return containsAny(values, condition.Values)
+	}
+}
+
+func containsAny(values []string, candidates []string) bool {
+	for _, value := range values {
+		for _, candidate := range candidates {
+			if value == candidate {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// conditionValues returns the values of an element the way the browser
+// submits them, an unchecked checkbox has no value.
+func conditionValues(element ElementInterface) []string {
+	if element.IsMultiple() {
+		return element.GetValues()
+	}
+	if element.GetType() == ElementTypeCheckbox && !element.IsChecked() {
+		return nil
+	}
+	if element.GetValue() == "" {
+		return nil
+	}
+	return []string{element.GetValue()}
+}
+
+// IsActive reports whether the conditions of the element hold. An inactive
+// element is treated as empty by the conditions depending on it, like the
+// disabled fields the browser does not submit.
+func (form *Form) IsActive(element ElementInterface) bool {
+	return form.isActive(element, 0)
+}
+
+func (form *Form) isActive(element ElementInterface, depth int) bool {
+	if depth > 10 {
+		return false
+	}
+	for _, condition := range element.GetConditions() {
+		var values []string
+		if dependency, err := form.Get(condition.Element); err == nil && form.isActive(dependency, depth+1) {
+			values = conditionValues(dependency)
+		}
+		if !condition.matches(values) {
+			return false
+		}
+	}
+	return true
+}
+
+// conditionsAttribute returns the conditions of the element as JSON with the
+// full names of the elements they depend on, which share the prefix of the
+// element, e.g. "address[country]" for "address[vat]".
+func conditionsAttribute(element ElementInterface) (string, error) {
+	prefix := ""
+	if name := element.GetName(); strings.HasSuffix(name, "]") {
+		if i := strings.LastIndex(name, "["); i > 0 {
+			prefix = name[:i]
+		}
+	}
+	var conditions []Condition
+	for _, condition := range element.GetConditions() {
+		resolved := *condition
+		resolved.Element = prefixName(prefix, condition.Element)
+		conditions = append(conditions, resolved)
+	}
+	b, err := json.Marshal(conditions)
+	return string(b), err
+}
+
+// renderConditionalTo wraps the markup of an element with conditions in a
+// div carrying them for ConditionScript.
+func renderConditionalTo(w io.Writer, element ElementInterface, render func(w io.Writer) error) error {
+	if len(element.GetConditions()) == 0 {
+		return render(w)
+	}
+	conditions, err := conditionsAttribute(element)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, `<div data-goform-conditions="`+template.HTMLEscapeString(conditions)+`">`); err != nil {
+		return err
+	}
+	if err := render(w); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, `</div>`)
+	return err
+}
+
+// ConditionScript shows and hides the elements with conditions in the browser
+// and disables the fields of hidden elements, so they are neither validated
+// by the browser nor submitted. Include it once in the page, e.g. with
+// ConditionScriptTag.
+const ConditionScript = `(function () {
+    function values(root, name) {
+        var result = [];
+        root.querySelectorAll('[name="' + name + '"], [name="' + name + '[]"]').forEach(function (field) {
+            if (field.disabled) {
+                return;
+            }
+            if (field.type === 'checkbox' || field.type === 'radio') {
+                if (field.checked) {
+                    result.push(field.value);
+                }
+                return;
+            }
+            if (field.tagName === 'SELECT') {
+                Array.prototype.forEach.call(field.selectedOptions, function (option) {
+                    result.push(option.value);
+                });
+                return;
+            }
+            if (field.value !== '') {
+                result.push(field.value);
+            }
+        });
+        return result;
+    }
+
+    function matches(root, condition) {
+        var current = values(root, condition.element);
+        var any = current.some(function (value) {
+            return (condition.values || []).indexOf(value) >= 0;
+        });
+        switch (condition.operator) {
+            case 'empty':
+                return current.length === 0;
+            case 'not-empty':
+                return current.length > 0;
+            case 'not-equals':
+                return !any;
+            default:
+                return any;
+        }
+    }
+
+    function update(root) {
+        // repeat while elements change, conditions may depend on elements with conditions
+        for (var pass = 0, changed = true; changed && pass < 10; pass++) {
+            changed = false;
+            root.querySelectorAll('[data-goform-conditions]').forEach(function (wrapper) {
+                var active = JSON.parse(wrapper.getAttribute('data-goform-conditions')).every(function (condition) {
+                    return matches(root, condition);
+                });
+                if (wrapper.hidden === !active) {
+                    return;
+                }
+                changed = true;
+                wrapper.hidden = !active;
+                wrapper.querySelectorAll('input, select, textarea, button').forEach(function (field) {
+                    if (!active && field.disabled) {
+                        field.setAttribute('data-goform-disabled', '');
+                    }
+                    field.disabled = !active || field.hasAttribute('data-goform-disabled');
+                });
+            });
+        }
+    }
+
+    function root(element) {
+        return element.closest('form') || document;
+    }
+
+    document.addEventListener('change', function (event) {
+        update(root(event.target));
+    });
+    document.addEventListener('input', function (event) {
+        update(root(event.target));
+    });
+    document.addEventListener('DOMContentLoaded', function () {
+        update(document);
+    });
+})();`
+
+// ConditionScriptTag returns ConditionScript in a script tag.
+func ConditionScriptTag() template.HTML {
+	return template.HTML("<script>" + ConditionScript + "</script>")
+}
@@ -0,0 +1,187 @@
+package goform
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestConditionOperators(t *testing.T) {
+	tests := []struct {
+		name      string
+		dependsOn string
+		condition Condition
+		values    url.Values
+		active    bool
+	}{
+		{"equals", "country", Condition{Operator: ConditionEquals, Values: []string{"TR", "DE"}}, url.Values{"country": {"DE"}}, true},
+		{"equals other", "country", Condition{Operator: ConditionEquals, Values: []string{"TR"}}, url.Values{"country": {"US"}}, false},
+		{"equals empty", "country", Condition{Operator: ConditionEquals, Values: []string{""}}, url.Values{}, false},
+		{"not equals", "country", Condition{Operator: ConditionNotEquals, Values: []string{"TR"}}, url.Values{"country": {"US"}}, true},
+		{"not equals same", "country", Condition{Operator: ConditionNotEquals, Values: []string{"TR"}}, url.Values{"country": {"TR"}}, false},
+		{"not equals empty", "country", Condition{Operator: ConditionNotEquals, Values: []string{"TR"}}, url.Values{}, true},
+		{"empty", "country", Condition{Operator: ConditionEmpty}, url.Values{"country": {""}}, true},
+		{"empty with value", "country", Condition{Operator: ConditionEmpty}, url.Values{"country": {"TR"}}, false},
+		{"not empty", "country", Condition{Operator: ConditionNotEmpty}, url.Values{"country": {"TR"}}, true},
+		{"not empty without value", "country", Condition{Operator: ConditionNotEmpty}, url.Values{}, false},
+		{"checked", "company", Condition{Operator: ConditionNotEmpty}, url.Values{"company": {"1"}}, true},
+		{"unchecked", "company", Condition{Operator: ConditionNotEmpty}, url.Values{}, false},
+		{"any of several values", "tags", Condition{Operator: ConditionEquals, Values: []string{"b"}}, url.Values{"tags[]": {"a", "b"}}, true},
+		{"none of several values", "tags", Condition{Operator: ConditionNotEquals, Values: []string{"c"}}, url.Values{"tags[]": {"a", "b"}}, true},
+		{"no values selected", "tags", Condition{Operator: ConditionEmpty}, url.Values{}, true},
+		{"missing element", "missing", Condition{Operator: ConditionEmpty}, url.Values{}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			form := NewGoForm()
+			form.Add(NewTextElement("country", "Country", nil, nil, nil))
+			form.Add(NewCheckboxElement("company", "Company", nil, nil, nil))
+			form.Add(NewMultiCheckboxElement("tags", "Tags", nil, []*ValueOption{{Value: "a"}, {Value: "b"}, {Value: "c"}}, nil, nil))
+			vat := NewTextElement("vat", "VAT", nil, nil, nil)
+			condition := test.condition
+			condition.Element = test.dependsOn
+			vat.AddCondition(&condition)
+			form.Add(vat)
+			form.bindValues(test.values)
+
+			if active := form.IsActive(vat); active != test.active {
+				t.Errorf("IsActive() = %v, want %v", active, test.active)
+			}
+		})
+	}
+}
+
+func TestIsActiveDependencies(t *testing.T) {
+	tests := []struct {
+		name   string
+		values url.Values
+		active map[string]bool
+	}{
+		{"chain active", url.Values{"a": {"x"}, "b": {"y"}}, map[string]bool{"b": true, "c": true}},
+		{"chain inactive", url.Values{"a": {"z"}, "b": {"y"}}, map[string]bool{"b": false, "c": false}},
+		{"end of chain empty", url.Values{"a": {"x"}}, map[string]bool{"b": true, "c": false}},
+		{"cycle", url.Values{"a": {"x"}, "b": {"y"}, "d": {"1"}, "e": {"1"}}, map[string]bool{"d": false, "e": false}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			form := NewGoForm()
+			a := NewTextElement("a", "A", nil, nil, nil)
+			b := NewTextElement("b", "B", nil, nil, nil)
+			b.AddCondition(&Condition{Element: "a", Operator: ConditionEquals, Values: []string{"x"}})
+			// c depends on b, which is treated as empty while it is inactive
+			c := NewTextElement("c", "C", nil, nil, nil)
+			c.AddCondition(&Condition{Element: "b", Operator: ConditionNotEmpty})
+			// d and e depend on each other, which is given up after 10 steps
+			d := NewTextElement("d", "D", nil, nil, nil)
+			d.AddCondition(&Condition{Element: "e", Operator: ConditionNotEmpty})
+			e := NewTextElement("e", "E", nil, nil, nil)
+			e.AddCondition(&Condition{Element: "d", Operator: ConditionNotEmpty})
+			for _, element := range []ElementInterface{a, b, c, d, e} {
+				form.Add(element)
+			}
+			form.bindValues(test.values)
+
+			for name, want := range test.active {
+				element, _ := form.Get(name)
+				if active := form.IsActive(element); active != want {
+					t.Errorf("IsActive(%s) = %v, want %v", name, active, want)
+				}
+			}
+		})
+	}
+}
+
+func newConditionalForm() *Form {
+	address := NewGoForm()
+	address.Add(NewTextElement("country", "Country", nil, nil, nil))
+	vat := NewTextElement("vat", "VAT", nil, []ValidatorInterface{&RequiredValidator{}}, nil)
+	vat.AddCondition(&Condition{Element: "country", Operator: ConditionEquals, Values: []string{"TR"}})
+	address.Add(vat)
+
+	form := NewGoForm()
+	form.Add(NewCheckboxElement("company", "Company", nil, nil, nil))
+	name := NewTextElement("company_name", "Company name", nil, []ValidatorInterface{&RequiredValidator{}}, nil)
+	name.AddCondition(&Condition{Element: "company", Operator: ConditionNotEmpty})
+	form.Add(name)
+	form.Add(NewSubFormElement("address", "Address", nil, address))
+	return form
+}
+
+func TestValidateSkipsInactive(t *testing.T) {
+	tests := []struct {
+		name   string
+		values url.Values
+		errors []string
+	}{
+		{"inactive", url.Values{"address[country]": {"US"}}, nil},
+		{"active and empty", url.Values{"company": {"1"}, "address[country]": {"TR"}}, []string{"company_name", "address[vat]"}},
+		{"active and filled", url.Values{"company": {"1"}, "company_name": {"Acme"}, "address[country]": {"TR"}, "address[vat]": {"1"}}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			form := newConditionalForm()
+			form.bindValues(test.values)
+			if valid := form.IsValid(); valid != (len(test.errors) == 0) {
+				t.Errorf("IsValid() = %v, errors %v", valid, form.GetErrorMessages())
+			}
+			messages := form.GetErrorMessages()
+			if len(messages) != len(test.errors) {
+				t.Errorf("errors = %v, want on %v", messages, test.errors)
+			}
+			for _, name := range test.errors {
+				if _, ok := messages[name]; !ok {
+					t.Errorf("no error on %s: %v", name, messages)
+				}
+			}
+		})
+	}
+}
+
+func TestMapToSkipsInactive(t *testing.T) {
+	type address struct {
+		Country string
+		Vat     string
+	}
+	type model struct {
+		Company     bool
+		CompanyName string
+		Address     address
+	}
+	form := newConditionalForm()
+	form.bindValues(url.Values{"company_name": {"Acme"}, "address[country]": {"US"}, "address[vat]": {"123"}})
+
+	m := model{CompanyName: "kept", Address: address{Vat: "kept"}}
+	if err := form.MapTo(&m); err != nil {
+		t.Fatal(err)
+	}
+	want := model{CompanyName: "kept", Address: address{Country: "US", Vat: "kept"}}
+	if m != want {
+		t.Errorf("MapTo() = %+v, want %+v", m, want)
+	}
+}
+
+func TestConditionsAttribute(t *testing.T) {
+	condition := &Condition{Element: "country", Operator: ConditionEquals, Values: []string{"TR"}}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"vat", `[{"element":"country","operator":"equals","values":["TR"]}]`},
+		{"address[vat]", `[{"element":"address[country]","operator":"equals","values":["TR"]}]`},
+		{"items[0][address][vat]", `[{"element":"items[0][address][country]","operator":"equals","values":["TR"]}]`},
+	}
+	for _, test := range tests {
+		element := NewTextElement(test.name, "VAT", nil, nil, nil)
+		element.AddCondition(condition)
+		if got, err := conditionsAttribute(element); err != nil || got != test.want {
+			t.Errorf("conditionsAttribute(%s) = %s, %v, want %s", test.name, got, err, test.want)
+		}
+	}
+
+	form := newConditionalForm()
+	out := form.Render()
+	want := `<div data-goform-conditions="[{&#34;element&#34;:&#34;address[country]&#34;,&#34;operator&#34;:&#34;equals&#34;,&#34;values&#34;:[&#34;TR&#34;]}]">`
+	if !strings.Contains(out, want) {
+		t.Errorf("rendered no %s:\n%s", want, out)
+	}
+}
@@ -0,0 +1,209 @@
+package goform
+
+import (
+	"encoding"
+	"errors"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Converter converts the values of a field type from and to the strings of
+// form values. Parse is not called for empty values, they map to the zero
+// value of the type.
+type Converter interface {
+	Parse(value string) (interface{}, error)
+	Format(value interface{}) (string, error)
+}
+
+// ConverterFuncs is a Converter of two functions.
+type ConverterFuncs struct {
+	ParseFunc  func(value string) (interface{}, error)
+	FormatFunc func(value interface{}) (string, error)
+}
+
+func (c ConverterFuncs) Parse(value string) (interface{}, error) {
+	return c.ParseFunc(value)
+}
+
+func (c ConverterFuncs) Format(value interface{}) (string, error) {
+	return c.FormatFunc(value)
+}
+
+var (
+	convertersMu sync.RWMutex
+	converters   = map[reflect.Type]Converter{}
+
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// RegisterConverter registers the converter for the type of v for all forms,
+// e.g. RegisterConverter(uuid.UUID{}, converter). Converters of a form take
+// precedence over them.
+//
+// Types without a converter implementing encoding.TextMarshaler and
+// encoding.TextUnmarshaler are converted with them.
+func RegisterConverter(v interface{}, converter Converter) {
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+	converters[reflect.TypeOf(v)] = converter
+}
+
+// RegisterConverter registers the converter for the type of v for the form,
+// its sub forms and collections.
+func (form *Form) RegisterConverter(v interface{}, converter Converter) {
+	if form.converters == nil {
+		form.converters = map[reflect.Type]Converter{}
+	}
+	form.converters[reflect.TypeOf(v)] = converter
+}
+
+// converterChain holds the converters of a form followed by the converters of
+// the forms it is nested in.
+type converterChain []map[reflect.Type]Converter
+
+// with returns the chain for a form nested in the forms of chain.
+func (chain converterChain) with(form *Form) converterChain {
+	return append(converterChain{form.converters}, chain...)
+}
+
+func (chain converterChain) lookup(typ reflect.Type) (Converter, bool) {
+	for _, c := range chain {
+		if converter, ok := c[typ]; ok {
+			return converter, true
+		}
+	}
+	convertersMu.RLock()
+	defer convertersMu.RUnlock()
+	converter, ok := converters[typ]
+	return converter, ok
+}
+
+// canParse reports whether values of typ are parsed as a whole by a converter
+// or a TextUnmarshaler, e.g. net.IP, instead of by their kind.
+func (chain converterChain) canParse(typ reflect.Type) bool {
+	if _, ok := chain.lookup(typ); ok {
+		return true
+	}
+	return typ != timeType && reflect.PtrTo(typ).Implements(textUnmarshalerType)
+}
+
+// canFormat reports whether values of typ are formatted as a whole by a
+// converter or a TextMarshaler instead of by their kind.
+func (chain converterChain) canFormat(typ reflect.Type) bool {
+	if _, ok := chain.lookup(typ); ok {
+		return true
+	}
+	return typ != timeType && (typ.Implements(textMarshalerType) || reflect.PtrTo(typ).Implements(textMarshalerType))
+}
+
+// parse converts v to typ with a converter, a TextUnmarshaler or by the kind
+// of typ. Dates and timestamps are parsed with the layout of field.
+func (chain converterChain) parse(field ElementInterface, v string, typ reflect.Type) (reflect.Value, error) {
+	if v == "" {
+		return reflect.Zero(typ), nil
+	}
+	if converter, ok := chain.lookup(typ); ok {
+		parsed, err := converter.Parse(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		value := reflect.ValueOf(parsed)
+		if !value.IsValid() {
+			return reflect.Zero(typ), nil
+		}
+		if !value.Type().ConvertibleTo(typ) {
+			return reflect.Value{}, errors.New("Converter returned " + value.Type().String() + " for " + typ.String())
+		}
+		return value.Convert(typ), nil
+	}
+	if typ == timeType || isTimestamp(typ) {
+		t, err := elementTime(field, v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if typ != timeType {
+			return timestampValue(typ, t), nil
+		}
+		return reflect.ValueOf(t), nil
+	}
+	if reflect.PtrTo(typ).Implements(textUnmarshalerType) {
+		value := reflect.New(typ)
+		if err := value.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(v)); err != nil {
+			return reflect.Value{}, err
+		}
+		return value.Elem(), nil
+	}
+	if names, ok := enumNames(typ); ok {
+		return parseEnum(v, typ, names)
+	}
+	if typ.Kind() == reflect.Struct {
+		if !isWrapperMessage(typ) {
+			return reflect.Value{}, ErrUnsupportedField
+		}
+		index, _ := wrapperField(typ)
+		wrapped, err := chain.parse(field, v, typ.Field(index).Type)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		value := reflect.New(typ).Elem()
+		value.Field(index).Set(wrapped)
+		return value, nil
+	}
+	return parseValue(v, typ)
+}
+
+// format converts value to a form value with a converter, a TextMarshaler or
+// by its kind.
+func (chain converterChain) format(value reflect.Value) (string, error) {
+	typ := value.Type()
+	if converter, ok := chain.lookup(typ); ok {
+		return converter.Format(value.Interface())
+	}
+	if typ == timeType {
+		return value.Interface().(time.Time).Format(LayoutDate), nil
+	}
+	if isTimestamp(typ) {
+		return timestampTime(value).Format(LayoutDate), nil
+	}
+	if typ.Implements(textMarshalerType) {
+		b, err := value.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), err
+	}
+	if reflect.PtrTo(typ).Implements(textMarshalerType) {
+		ptr := reflect.New(typ)
+		ptr.Elem().Set(value)
+		b, err := ptr.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), err
+	}
+
+	if names, ok := enumNames(typ); ok {
+		return formatEnum(value, names), nil
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, typ.Bits()), nil
+	case reflect.Ptr:
+		if value.IsNil() {
+			return "", nil
+		}
+		return chain.format(value.Elem())
+	case reflect.Struct:
+		if isWrapperMessage(typ) {
+			index, _ := wrapperField(typ)
+			return chain.format(value.Field(index))
+		}
+	}
+	return "", ErrUnsupportedField
+}
@@ -0,0 +1,264 @@
+package goform
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testSku string
+
+type testPoint struct{ X, Y int }
+
+// MarshalText has a pointer receiver, so only *testPoint is a TextMarshaler.
+func (p *testPoint) MarshalText() ([]byte, error) {
+	return []byte(strconv.Itoa(p.X) + "," + strconv.Itoa(p.Y)), nil
+}
+
+func (p *testPoint) UnmarshalText(b []byte) error {
+	x, y, ok := strings.Cut(string(b), ",")
+	if !ok {
+		return errors.New("invalid point")
+	}
+	var err error
+	if p.X, err = strconv.Atoi(x); err != nil {
+		return err
+	}
+	p.Y, err = strconv.Atoi(y)
+	return err
+}
+
+// prefixConverter parses by cutting prefix and formats by adding it.
+func prefixConverter(prefix string) Converter {
+	return ConverterFuncs{
+		ParseFunc: func(value string) (interface{}, error) {
+			if !strings.HasPrefix(value, prefix) {
+				return nil, errors.New("missing prefix " + prefix)
+			}
+			return testSku(strings.TrimPrefix(value, prefix)), nil
+		},
+		FormatFunc: func(value interface{}) (string, error) {
+			return prefix + string(value.(testSku)), nil
+		},
+	}
+}
+
+func registerTestConverter(t *testing.T, v interface{}, converter Converter) {
+	RegisterConverter(v, converter)
+	t.Cleanup(func() {
+		convertersMu.Lock()
+		defer convertersMu.Unlock()
+		delete(converters, reflect.TypeOf(v))
+	})
+}
+
+func TestConverterPrecedence(t *testing.T) {
+	type model struct {
+		Sku testSku `goform:"sku;type=text"`
+	}
+	tests := []struct {
+		name   string
+		global Converter
+		form   Converter
+		value  string
+	}{
+		{"kind", nil, nil, "A-1"},
+		{"global", prefixConverter("g:"), nil, "g:A-1"},
+		{"form", nil, prefixConverter("f:"), "f:A-1"},
+		{"form before global", prefixConverter("g:"), prefixConverter("f:"), "f:A-1"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.global != nil {
+				registerTestConverter(t, testSku(""), test.global)
+			}
+			form, err := NewFormFromStruct(&model{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.form != nil {
+				form.RegisterConverter(testSku(""), test.form)
+			}
+
+			form.BindFromInterface(&model{Sku: "A-1"})
+			sku, _ := form.Get("sku")
+			if sku.GetValue() != test.value {
+				t.Errorf("bound %q, want %q", sku.GetValue(), test.value)
+			}
+			var m model
+			if err := form.MapTo(&m); err != nil || m.Sku != "A-1" {
+				t.Errorf("MapTo() = %q, %v, want %q", m.Sku, err, "A-1")
+			}
+		})
+	}
+}
+
+func TestConverterChainNested(t *testing.T) {
+	type line struct {
+		Sku testSku `goform:"sku;type=text"`
+	}
+	type model struct {
+		Main  line
+		Other line
+		Lines []line
+	}
+	form, err := NewFormFromStruct(&model{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	form.RegisterConverter(testSku(""), prefixConverter("f:"))
+	other, _ := form.Get("other")
+	other.(*SubFormElement).Form.RegisterConverter(testSku(""), prefixConverter("o:"))
+
+	in := model{Main: line{"A"}, Other: line{"B"}, Lines: []line{{"C"}}}
+	form.BindFromInterface(&in)
+	for name, want := range map[string]string{"main[sku]": "f:A", "other[sku]": "o:B", "lines[0][sku]": "f:C"} {
+		var element ElementInterface
+		switch {
+		case strings.HasPrefix(name, "main"):
+			main, _ := form.Get("main")
+			element, _ = main.(*SubFormElement).Form.Get("sku")
+		case strings.HasPrefix(name, "other"):
+			element, _ = other.(*SubFormElement).Form.Get("sku")
+		default:
+			lines, _ := form.Get("lines")
+			element, _ = lines.(*CollectionElement).Rows[0].Get("sku")
+		}
+		if element.GetValue() != want {
+			t.Errorf("%s = %q, want %q", name, element.GetValue(), want)
+		}
+	}
+
+	var out model
+	if err := form.MapTo(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("MapTo() = %+v, want %+v", out, in)
+	}
+}
+
+func TestConverterTextMarshaler(t *testing.T) {
+	tests := []struct {
+		typ       reflect.Type
+		canParse  bool
+		canFormat bool
+	}{
+		{reflect.TypeOf(net.IP{}), true, true},
+		{reflect.TypeOf(testPoint{}), true, true},
+		{reflect.TypeOf(time.Time{}), false, false},
+		{reflect.TypeOf(testSku("")), false, false},
+		{reflect.TypeOf(0), false, false},
+	}
+	for _, test := range tests {
+		if got := converterChain(nil).canParse(test.typ); got != test.canParse {
+			t.Errorf("canParse(%s) = %v, want %v", test.typ, got, test.canParse)
+		}
+		if got := converterChain(nil).canFormat(test.typ); got != test.canFormat {
+			t.Errorf("canFormat(%s) = %v, want %v", test.typ, got, test.canFormat)
+		}
+	}
+
+	type model struct {
+		IP    net.IP
+		Point testPoint
+	}
+	form, err := NewFormFromStruct(&model{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := model{IP: net.ParseIP("192.0.2.1").To4(), Point: testPoint{3, 4}}
+	form.BindFromInterface(&in)
+	point, _ := form.Get("point")
+	if point.GetValue() != "3,4" {
+		t.Errorf("point = %q, want %q", point.GetValue(), "3,4")
+	}
+	out, err := Bind[model](form)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !out.IP.Equal(in.IP) || out.Point != in.Point {
+		t.Errorf("Bind() = %+v, want %+v", out, in)
+	}
+
+	form.bindValues(url.Values{"point": {"3"}})
+	if _, err := Bind[model](form); err == nil || !strings.Contains(err.Error(), "invalid point") {
+		t.Errorf("Bind() error = %v, want invalid point", err)
+	}
+}
+
+func TestConvertUint64(t *testing.T) {
+	type model struct {
+		Id uint64 `goform:"id"`
+	}
+	tests := []struct {
+		value   string
+		id      uint64
+		message string
+	}{
+		{"18446744073709551615", 18446744073709551615, ""},
+		{"9007199254740993", 9007199254740993, ""},
+		{"18446744073709551616", 0, "Value is out of range"},
+		{"-1", 0, "Value must be a whole number"},
+	}
+	for _, test := range tests {
+		form, err := NewFormFromStruct(&model{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		form.BindFromInterface(&model{Id: test.id})
+		if id, _ := form.Get("id"); test.message == "" && id.GetValue() != test.value {
+			t.Errorf("bound %q, want %q", id.GetValue(), test.value)
+		}
+
+		form.bindValues(url.Values{"id": {test.value}})
+		m, err := Bind[model](form)
+		id, _ := form.Get("id")
+		if test.message == "" {
+			if err != nil || m.Id != test.id {
+				t.Errorf("Bind(%s) = %d, %v, want %d", test.value, m.Id, err, test.id)
+			}
+			continue
+		}
+		if messages := id.GetErrorMessages(); err == nil || len(messages) != 1 || messages[0] != test.message {
+			t.Errorf("Bind(%s) error = %v, messages %v, want %q", test.value, err, messages, test.message)
+		}
+	}
+}
+
+func TestConverterReturnedWrongType(t *testing.T) {
+	type model struct {
+		Sku testSku `goform:"sku;type=text"`
+	}
+	form, err := NewFormFromStruct(&model{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	form.RegisterConverter(testSku(""), ConverterFuncs{
+		ParseFunc:  func(value string) (interface{}, error) { return float64(len(value)), nil },
+		FormatFunc: func(value interface{}) (string, error) { return string(value.(testSku)), nil },
+	})
+	form.bindValues(url.Values{"sku": {"A-1"}})
+
+	var m model
+	err = form.MapTo(&m)
+	var fieldErrors FieldErrors
+	if !errors.As(err, &fieldErrors) || len(fieldErrors) != 1 {
+		t.Fatalf("MapTo() error = %v, want one field error", err)
+	}
+	if want := "Converter returned float64 for goform.testSku"; fieldErrors[0].Err.Error() != want {
+		t.Errorf("error = %q, want %q", fieldErrors[0].Err, want)
+	}
+	if m.Sku != "" {
+		t.Errorf("sku = %q, want it unchanged", m.Sku)
+	}
+	sku, _ := form.Get("sku")
+	if messages := sku.GetErrorMessages(); len(messages) != 1 || messages[0] != "Value is not valid" {
+		t.Errorf("errors = %v, want %q", messages, "Value is not valid")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/vincent-petithory/dataurl"
+	"html/template"
 	"io"
 	"io/ioutil"
 	"mime/multipart"
@@ -31,6 +32,15 @@ const (
 	ElementTypeSubmit        ElementType = "submit"
 	ElementTypeImage         ElementType = "image"
 	ElementTypeCaptcha       ElementType = "captcha"
+	ElementTypeDate          ElementType = "date"
+	ElementTypeTime          ElementType = "time"
+	ElementTypeDateTimeLocal ElementType = "datetime-local"
+	ElementTypeMonth         ElementType = "month"
+	ElementTypeWeek          ElementType = "week"
+	ElementTypeMultiSelect   ElementType = "multiselect"
+	ElementTypeCollection    ElementType = "collection"
+	ElementTypeSubForm       ElementType = "subform"
+	ElementTypeMultiFile     ElementType = "multifile"
 )
 
 var (
@@ -47,12 +57,16 @@ type ElementInterface interface {
 	GetName() string
 	SetName(string)
 	GetLabel() string
+	SetLabelHTML(template.HTML)
+	RenderLabel() template.HTML
 	SetValue(string)
 	SetValues([]string)
 	GetValue() string
 	GetValues() []string
 	SetFile(file *File)
 	GetFile() *File
+	SetFiles(files []*File)
+	GetFiles() []*File
 
 	GetDeletionUrl() string
 	SetDeletionUrl(string)
@@ -60,11 +74,18 @@ type ElementInterface interface {
 	AddValueOption(valueOption *ValueOption)
 	ClearValueOptions()
 
+	IsMultiple() bool
 	IsChecked() bool
 	IsCheckedInValues(string) bool
+	IsRequired() bool
+	GetConstraintAttributes() []*Attribute
+	SetNoValidate(bool)
 
 	IsValid() bool
 
+	AddCondition(condition *Condition)
+	GetConditions() []*Condition
+
 	AddValidator(validator ValidatorInterface)
 	GetValidators() []ValidatorInterface
 	ClearValidators()
@@ -73,11 +94,21 @@ type ElementInterface interface {
 	ApplyFilters()
 
 	GetErrors() []Message
+	GetErrorMessages() []string
 	AddError(string, []interface{})
+	ClearErrors()
+
+	GetTranslator() Translator
+	SetTranslator(Translator)
 
 	Render() string
+	RenderTo(w io.Writer) error
 	GetTheme() Theme
 	SetTheme(Theme)
+	GetThemeOverride() Theme
+	SetThemeOverride(Theme)
+	GetThemeLoader() *ThemeLoader
+	SetThemeLoader(*ThemeLoader)
 	GetTemplateFunctions() map[string]interface{}
 	SetTemplateFunctions(map[string]interface{})
 }
@@ -87,6 +118,7 @@ type File struct {
 	Name      string
 	Extension string
 	Location  string
+	Size      int64
 	Binary    multipart.File
 }
 
@@ -136,6 +168,15 @@ func (file *File) Save() error {
 type Attribute struct {
 	Key   string
 	Value string
+
+	trusted bool
+}
+
+// TrustedAttribute returns an attribute which is rendered as it is, e.g. an
+// event handler which is otherwise dropped or a style which is otherwise
+// sanitized. Its value must not come from user input.
+func TrustedAttribute[T template.JS | template.CSS | template.HTML | template.URL](key string, value T) *Attribute {
+	return &Attribute{Key: key, Value: string(value), trusted: true}
 }
 
 type ValueOption struct {
@@ -148,6 +189,7 @@ type ValueOption struct {
 type Element struct {
 	Type              ElementType
 	Label             string
+	LabelHTML         template.HTML
 	Name              string
 	Attributes        []*Attribute
 	Value             string
@@ -157,9 +199,15 @@ type Element struct {
 	Filters           []FilterInterface
 	Errors            []Message
 	File              *File
+	Files             []*File
+	Conditions        []*Condition
 	deletionUrl       string
 	theme             Theme
+	themeOverride     Theme
+	themeLoader       *ThemeLoader
 	templateFunctions map[string]interface{}
+	translator        Translator
+	noValidate        bool
 }
 
 func (element *Element) GetType() ElementType {
@@ -178,6 +226,21 @@ func (element *Element) GetLabel() string {
 	return element.Label
 }
 
+// SetLabelHTML marks the label as trusted markup. It is written to the page
+// as is, so it must never contain user supplied content.
+func (element *Element) SetLabelHTML(html template.HTML) {
+	element.LabelHTML = html
+}
+
+// RenderLabel returns the trusted LabelHTML when set, otherwise the escaped
+// plain text Label.
+func (element *Element) RenderLabel() template.HTML {
+	if element.LabelHTML != "" {
+		return element.LabelHTML
+	}
+	return template.HTML(template.HTMLEscapeString(element.Label))
+}
+
 func (element *Element) SetValue(s string) {
 	element.Value = s
 	for _, v := range element.Validators {
@@ -187,13 +250,9 @@ func (element *Element) SetValue(s string) {
 		f.SetValue(s)
 	}
 
-	if element.ValueOptions != nil {
-		for _, valueOption := range element.ValueOptions {
-			if valueOption.Value == s {
-				valueOption.Selected = true
-				break
-			}
-		}
+	// binding replaces the selection, options selected before are cleared
+	for _, valueOption := range element.ValueOptions {
+		valueOption.Selected = valueOption.Value == s
 	}
 }
 
@@ -205,6 +264,10 @@ func (element *Element) SetValues(s []string) {
 	for _, f := range element.Filters {
 		f.SetValues(s)
 	}
+
+	for _, valueOption := range element.ValueOptions {
+		valueOption.Selected = element.IsCheckedInValues(valueOption.Value)
+	}
 }
 
 func (element *Element) GetValue() string {
@@ -229,6 +292,14 @@ func (element *Element) GetFile() *File {
 	return element.File
 }
 
+func (element *Element) SetFiles(files []*File) {
+	element.Files = files
+}
+
+func (element *Element) GetFiles() []*File {
+	return element.Files
+}
+
 func (element *Element) BinaryToString() string {
 	var s string
 
@@ -251,13 +322,50 @@ func (element *Element) ClearValueOptions() {
 	element.ValueOptions = nil
 }
 
+// IsMultiple reports whether the element holds several values, which are
+// bound to Values instead of Value, or several files.
+func (element *Element) IsMultiple() bool {
+	return element.Type == ElementTypeMultiCheckbox || element.Type == ElementTypeMultiSelect || element.Type == ElementTypeMultiFile
+}
+
 func (element *Element) IsChecked() bool {
 	return element.Value != "" && element.Value != "false"
 }
 
+// IsRequired reports whether a RequiredValidator is attached to the element.
+func (element *Element) IsRequired() bool {
+	for _, v := range element.Validators {
+		if _, ok := v.(*RequiredValidator); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// GetConstraintAttributes returns the HTML5 constraint attributes, such as
+// required or maxlength, matching the validators of the element. Themes render
+// them after the explicit Attributes, which take precedence.
+func (element *Element) GetConstraintAttributes() []*Attribute {
+	if element.noValidate {
+		return nil
+	}
+	var attributes []*Attribute
+	for _, v := range element.Validators {
+		if v, ok := v.(ConstraintValidator); ok {
+			attributes = append(attributes, v.Constraints()...)
+		}
+	}
+	return attributes
+}
+
+func (element *Element) SetNoValidate(noValidate bool) {
+	element.noValidate = noValidate
+}
+
 func (element *Element) IsValid() bool {
 	var errs []Message
 	for _, v := range element.Validators {
+		clearMessages(v)
 		if !v.IsValid() {
 			errs = append(errs, v.GetMessages()...)
 		}
@@ -320,6 +428,16 @@ func (element *Element) SetAttribute(key string, value string) {
 	element.AddAttribute(&Attribute{Key: key, Value: value})
 }
 
+// AddCondition makes the element depend on another element of the form, see
+// Condition.
+func (element *Element) AddCondition(condition *Condition) {
+	element.Conditions = append(element.Conditions, condition)
+}
+
+func (element *Element) GetConditions() []*Condition {
+	return element.Conditions
+}
+
 func (element *Element) AddValidator(validator ValidatorInterface) {
 	validator.SetValue(element.GetValue())
 	validator.SetValues(element.GetValues())
@@ -339,10 +457,31 @@ func (element *Element) GetErrors() []Message {
 	return element.Errors
 }
 
+// GetErrorMessages returns the errors translated and formatted for display.
+func (element *Element) GetErrorMessages() []string {
+	var messages []string
+	for _, message := range element.Errors {
+		messages = append(messages, message.Format(element.translator))
+	}
+	return messages
+}
+
 func (element *Element) AddError(s string, args []interface{}) {
 	element.Errors = append(element.Errors, Message{Message: s, Args: args})
 }
 
+func (element *Element) ClearErrors() {
+	element.Errors = nil
+}
+
+func (element *Element) GetTranslator() Translator {
+	return element.translator
+}
+
+func (element *Element) SetTranslator(translator Translator) {
+	element.translator = translator
+}
+
 func (element *Element) GetTheme() Theme {
 	return element.theme
 }
@@ -351,6 +490,28 @@ func (element *Element) SetTheme(theme Theme) {
 	element.theme = theme
 }
 
+func (element *Element) GetThemeOverride() Theme {
+	return element.themeOverride
+}
+
+// SetThemeOverride replaces blocks of the form theme for this element only,
+// e.g. a {{define "select"}} block used by a single select element.
+func (element *Element) SetThemeOverride(theme Theme) {
+	element.themeOverride = theme
+}
+
+func (element *Element) GetThemeLoader() *ThemeLoader {
+	return element.themeLoader
+}
+
+// SetThemeLoader makes the element render with the theme of the loader, which
+// takes precedence over the theme set with SetTheme.
+func (element *Element) SetThemeLoader(loader *ThemeLoader) {
+	element.themeLoader = loader
+}
+
+// SetTemplateFunctions sets the functions available to the theme. The theme is
+// compiled once per set of functions, elements with equal functions share it.
 func (element *Element) SetTemplateFunctions(templateFunctions map[string]interface{}) {
 	element.templateFunctions = templateFunctions
 }
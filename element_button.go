@@ -1,5 +1,7 @@
 package goform
 
+import "io"
+
 type ButtonElement struct {
 	Element
 }
@@ -17,3 +19,7 @@ func NewButtonElement(name string, label string, attributes []*Attribute) *Butto
 func (element *ButtonElement) Render() string {
 	return renderTemplate(ElementTypeButton, element)
 }
+
+func (element *ButtonElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeButton, element)
+}
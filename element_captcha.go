@@ -0,0 +1,45 @@
+package goform
+
+import "io"
+
+type CaptchaElement struct {
+	Element
+	Provider CaptchaProvider
+	response CaptchaResponse
+}
+
+// NewCaptchaElement creates a captcha backed by provider. The element carries
+// a CaptchaValidator, so the answer is verified by Form.IsValid.
+func NewCaptchaElement(name string, label string, attributes []*Attribute, provider CaptchaProvider) *CaptchaElement {
+	element := new(CaptchaElement)
+	element.Type = ElementTypeCaptcha
+	element.Name = name
+	element.Label = label
+	element.Attributes = attributes
+	element.Provider = provider
+	element.Validators = []ValidatorInterface{&CaptchaValidator{element: element}}
+
+	return element
+}
+
+// GetChallenge creates a new challenge for the element, themes call it once per
+// render.
+func (element *CaptchaElement) GetChallenge() (*CaptchaChallenge, error) {
+	return element.Provider.Challenge(element.Name)
+}
+
+func (element *CaptchaElement) SetResponse(response CaptchaResponse) {
+	element.response = response
+}
+
+func (element *CaptchaElement) GetResponse() CaptchaResponse {
+	return element.response
+}
+
+func (element *CaptchaElement) Render() string {
+	return renderTemplate(ElementTypeCaptcha, element)
+}
+
+func (element *CaptchaElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeCaptcha, element)
+}
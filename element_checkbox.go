@@ -1,5 +1,7 @@
 package goform
 
+import "io"
+
 type CheckboxElement struct {
 	Element
 }
@@ -19,3 +21,7 @@ func NewCheckboxElement(name string, label string, attributes []*Attribute, vali
 func (element *CheckboxElement) Render() string {
 	return renderTemplate(ElementTypeCheckbox, element)
 }
+
+func (element *CheckboxElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeCheckbox, element)
+}
@@ -1,5 +1,7 @@
 package goform
 
+import "io"
+
 type MultiCheckboxElement struct {
 	Element
 }
@@ -20,3 +22,7 @@ func NewMultiCheckboxElement(name string, label string, attributes []*Attribute,
 func (element *MultiCheckboxElement) Render() string {
 	return renderTemplate(ElementTypeMultiCheckbox, element)
 }
+
+func (element *MultiCheckboxElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeMultiCheckbox, element)
+}
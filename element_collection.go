@@ -0,0 +1,351 @@
+package goform
+
+import (
+	"html/template"
+	"io"
+	"mime/multipart"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CollectionPrototypeIndex is the row index placeholder in the names of the
+// prototype row, scripts replace it with the index of the row they add.
+const CollectionPrototypeIndex = "__index__"
+
+// CollectionElement repeats a set of elements in rows named after the index of
+// the row, e.g. "items[0][sku]". Prototype creates the elements of a row, each
+// row is a Form of its own which binds, validates and maps like any form.
+type CollectionElement struct {
+	Element
+	Prototype func() []ElementInterface
+	Min       int
+	Max       int
+	Rows      []*Form
+	submitted []int
+}
+
+// NewCollectionElement creates a collection with min rows, which are at least
+// required and rendered empty. A max of 0 does not limit the number of rows.
+func NewCollectionElement(name string, label string, attributes []*Attribute, prototype func() []ElementInterface, min int, max int) *CollectionElement {
+	element := new(CollectionElement)
+	element.Type = ElementTypeCollection
+	element.Name = name
+	element.Label = label
+	element.Attributes = attributes
+	element.Prototype = prototype
+	element.Min = min
+	element.Max = max
+	for i := 0; i < min; i++ {
+		element.AddRow()
+	}
+
+	return element
+}
+
+// AddRow appends an empty row and returns it.
+func (element *CollectionElement) AddRow() *Form {
+	row := element.newRow(strconv.Itoa(len(element.Rows)))
+	element.Rows = append(element.Rows, row)
+	return row
+}
+
+func (element *CollectionElement) GetRows() []*Form {
+	return element.Rows
+}
+
+// GetPrototype returns a row named with CollectionPrototypeIndex.
+func (element *CollectionElement) GetPrototype() *Form {
+	return element.newRow(CollectionPrototypeIndex)
+}
+
+func (element *CollectionElement) newRow(index string) *Form {
+	row := NewGoForm()
+	row.namePrefix = element.Name + "[" + index + "]"
+	row.theme = element.theme
+	row.themeLoader = element.themeLoader
+	row.templateFunctions = element.templateFunctions
+	row.translator = element.translator
+	row.noValidate = element.noValidate
+	for _, e := range element.Prototype() {
+		row.Add(e)
+	}
+	return row
+}
+
+func (element *CollectionElement) IsMultipart() bool {
+	return element.GetPrototype().IsMultipart()
+}
+
+// bindValues replaces the rows with the rows submitted in val. Rows are
+// numbered again from 0 in the order of their submitted index, so rows removed
+// on the client leave no gaps.
+func (element *CollectionElement) bindValues(val url.Values) {
+	element.Rows = nil
+	indexes, values := submittedRows(val, element.Name, element.rowLimit())
+	element.submitted = indexes
+	for _, index := range indexes {
+		row := element.AddRow()
+		row.bindValues(rowValues(values[index], row))
+	}
+}
+
+// bindFiles binds the files of the submitted rows. Rows submitted with files
+// only are added in the order of their index.
+func (element *CollectionElement) bindFiles(headers map[string][]*multipart.FileHeader) {
+	indexes, files := submittedRows(headers, element.Name, element.rowLimit())
+	element.addSubmittedRows(indexes)
+	for i, row := range element.Rows {
+		row.bindFiles(rowValues(files[element.submittedIndex(i)], row))
+	}
+}
+
+// rowLimit returns the number of rows bound at most, one more than Max so
+// validation reports the rows above it, or 0 without a limit.
+func (element *CollectionElement) rowLimit() int {
+	if element.Max > 0 {
+		return element.Max + 1
+	}
+	return 0
+}
+
+// submittedIndex returns the index row i was submitted with.
+func (element *CollectionElement) submittedIndex(i int) int {
+	if i < len(element.submitted) {
+		return element.submitted[i]
+	}
+	return i
+}
+
+// addSubmittedRows adds empty rows for the indexes which were not submitted
+// before and numbers the rows again.
+func (element *CollectionElement) addSubmittedRows(indexes []int) {
+	rows := map[int]*Form{}
+	for i, row := range element.Rows {
+		rows[element.submittedIndex(i)] = row
+	}
+	merged := append([]int(nil), indexes...)
+	for index := range rows {
+		merged = append(merged, index)
+	}
+	sort.Ints(merged)
+	if len(merged) == 0 {
+		return
+	}
+	limit := element.rowLimit()
+
+	element.Rows = nil
+	element.submitted = nil
+	for i, index := range merged {
+		if i > 0 && index == merged[i-1] {
+			continue
+		}
+		if limit > 0 && len(element.Rows) == limit {
+			break
+		}
+		if row, ok := rows[index]; ok {
+			row.setNamePrefix(element.Name + "[" + strconv.Itoa(len(element.Rows)) + "]")
+			element.Rows = append(element.Rows, row)
+		} else {
+			element.AddRow()
+		}
+		element.submitted = append(element.submitted, index)
+	}
+}
+
+// submittedRows groups the values of the rows of the collection name by their
+// submitted index, keyed by their name within the row, e.g. "[sku]" for
+// "items[3][sku]". The indexes are sorted, with a limit above 0 only the lowest
+// limit indexes are kept.
+func submittedRows[V any](values map[string]V, name string, limit int) ([]int, map[int]map[string]V) {
+	prefix := name + "["
+	rows := map[int]map[string]V{}
+	for key, value := range values {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		end := strings.Index(key[len(prefix):], "]")
+		if end < 0 {
+			continue
+		}
+		index, err := strconv.Atoi(key[len(prefix) : len(prefix)+end])
+		if err != nil || index < 0 {
+			continue
+		}
+		row, ok := rows[index]
+		if !ok {
+			row = map[string]V{}
+			rows[index] = row
+		}
+		row[key[len(prefix)+end+1:]] = value
+	}
+	indexes := make([]int, 0, len(rows))
+	for index := range rows {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	if limit > 0 && len(indexes) > limit {
+		for _, index := range indexes[limit:] {
+			delete(rows, index)
+		}
+		indexes = indexes[:limit]
+	}
+	return indexes, rows
+}
+
+// rowValues names the values grouped by submittedRows after row, e.g. "[sku]"
+// is "items[1][sku]" for the second row.
+func rowValues[V any](values map[string]V, row *Form) map[string]V {
+	named := make(map[string]V, len(values))
+	for key, value := range values {
+		named[row.namePrefix+key] = value
+	}
+	return named
+}
+
+// bindSlice replaces the rows with one row per item of a slice of structs.
+func (element *CollectionElement) bindSlice(slice reflect.Value, chain converterChain) {
+	element.Rows = nil
+	element.submitted = nil
+	for i := 0; i < slice.Len(); i++ {
+		item := slice.Index(i)
+		if item.Kind() == reflect.Ptr {
+			if item.IsNil() {
+				continue
+			}
+			item = item.Elem()
+		}
+		if item.Kind() == reflect.Struct {
+			element.AddRow().bindStruct(item, chain)
+		}
+	}
+}
+
+// mapTo maps the rows to a slice of structs, e.g. []Item or []*Item. The
+// slice is left as it is when a row cannot be mapped.
+func (element *CollectionElement) mapTo(field reflect.Value, path string, chain converterChain) FieldErrors {
+	typ := field.Type()
+	if typ.Kind() != reflect.Slice {
+		return FieldErrors{{Field: path, Element: element.Name, Err: ErrUnsupportedField}}
+	}
+	itemType := typ.Elem()
+	isPtr := itemType.Kind() == reflect.Ptr
+	if isPtr {
+		itemType = itemType.Elem()
+	}
+	if itemType.Kind() != reflect.Struct {
+		return FieldErrors{{Field: path, Element: element.Name, Err: ErrUnsupportedField}}
+	}
+	if len(element.Rows) == 0 {
+		field.Set(reflect.Zero(typ))
+		return nil
+	}
+	var errs FieldErrors
+	slice := reflect.MakeSlice(typ, 0, len(element.Rows))
+	for i, row := range element.Rows {
+		item := reflect.New(itemType)
+		errs = append(errs, row.mapStruct(item.Elem(), path+"["+strconv.Itoa(i)+"].", chain)...)
+		if isPtr {
+			slice = reflect.Append(slice, item)
+		} else {
+			slice = reflect.Append(slice, item.Elem())
+		}
+	}
+	if len(errs) == 0 {
+		field.Set(slice)
+	}
+	return errs
+}
+
+// IsValid validates every row, errors are reported on the elements of the
+// rows, and the number of rows, which is reported on the collection.
+func (element *CollectionElement) IsValid() bool {
+	valid := true
+	for _, row := range element.Rows {
+		if !row.validate() {
+			valid = false
+		}
+	}
+	var errs []Message
+	if len(element.Rows) < element.Min {
+		errs = append(errs, Message{
+			Message: "At least %d rows are required",
+			Args:    []interface{}{element.Min},
+		})
+	}
+	if element.Max > 0 && len(element.Rows) > element.Max {
+		errs = append(errs, Message{
+			Message: "At most %d rows are allowed",
+			Args:    []interface{}{element.Max},
+		})
+	}
+	if len(errs) > 0 {
+		element.Errors = errs
+		return false
+	}
+	return valid
+}
+
+func (element *CollectionElement) ApplyFilters() {
+	for _, row := range element.Rows {
+		row.ApplyFilters()
+	}
+}
+
+func (element *CollectionElement) SetName(name string) {
+	element.Name = name
+	for i, row := range element.Rows {
+		row.setNamePrefix(name + "[" + strconv.Itoa(i) + "]")
+	}
+}
+
+func (element *CollectionElement) SetTheme(theme Theme) {
+	element.Element.SetTheme(theme)
+	for _, row := range element.Rows {
+		row.SetTheme(theme)
+	}
+}
+
+func (element *CollectionElement) SetThemeLoader(loader *ThemeLoader) {
+	element.Element.SetThemeLoader(loader)
+	for _, row := range element.Rows {
+		row.SetThemeLoader(loader)
+	}
+}
+
+func (element *CollectionElement) SetTemplateFunctions(templateFunctions map[string]interface{}) {
+	element.Element.SetTemplateFunctions(templateFunctions)
+	for _, row := range element.Rows {
+		row.SetTemplateFunctions(templateFunctions)
+	}
+}
+
+func (element *CollectionElement) SetTranslator(translator Translator) {
+	element.Element.SetTranslator(translator)
+	for _, row := range element.Rows {
+		row.SetTranslator(translator)
+	}
+}
+
+func (element *CollectionElement) SetNoValidate(noValidate bool) {
+	element.Element.SetNoValidate(noValidate)
+	for _, row := range element.Rows {
+		row.SetNoValidate(noValidate)
+	}
+}
+
+// RenderPrototype renders the elements of the prototype row, themes put it in
+// a template tag for scripts to clone.
+func (element *CollectionElement) RenderPrototype() (template.HTML, error) {
+	return element.GetPrototype().RenderElements()
+}
+
+func (element *CollectionElement) Render() string {
+	return renderTemplate(ElementTypeCollection, element)
+}
+
+func (element *CollectionElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeCollection, element)
+}
@@ -0,0 +1,22 @@
+package goform
+
+import "io"
+
+type DateElement struct {
+	TemporalElement
+}
+
+func NewDateElement(name string, label string, attributes []*Attribute, validators []ValidatorInterface, filters []FilterInterface) *DateElement {
+	element := new(DateElement)
+	newTemporalElement(&element.TemporalElement, ElementTypeDate, LayoutDate, name, label, attributes, validators, filters)
+
+	return element
+}
+
+func (element *DateElement) Render() string {
+	return renderTemplate(ElementTypeText, element)
+}
+
+func (element *DateElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeText, element)
+}
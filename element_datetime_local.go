@@ -0,0 +1,22 @@
+package goform
+
+import "io"
+
+type DateTimeLocalElement struct {
+	TemporalElement
+}
+
+func NewDateTimeLocalElement(name string, label string, attributes []*Attribute, validators []ValidatorInterface, filters []FilterInterface) *DateTimeLocalElement {
+	element := new(DateTimeLocalElement)
+	newTemporalElement(&element.TemporalElement, ElementTypeDateTimeLocal, LayoutDateTimeLocal, name, label, attributes, validators, filters)
+
+	return element
+}
+
+func (element *DateTimeLocalElement) Render() string {
+	return renderTemplate(ElementTypeText, element)
+}
+
+func (element *DateTimeLocalElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeText, element)
+}
@@ -1,5 +1,7 @@
 package goform
 
+import "io"
+
 type EmailElement struct {
 	Element
 }
@@ -19,3 +21,7 @@ func NewEmailElement(name string, label string, attributes []*Attribute, validat
 func (element *EmailElement) Render() string {
 	return renderTemplate(ElementTypeText, element)
 }
+
+func (element *EmailElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeText, element)
+}
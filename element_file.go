@@ -1,5 +1,7 @@
 package goform
 
+import "io"
+
 type FileElement struct {
 	Element
 }
@@ -20,3 +22,7 @@ func NewFileElement(name string, label string, attributes []*Attribute, validato
 func (element *FileElement) Render() string {
 	return renderTemplate(ElementTypeFile, element)
 }
+
+func (element *FileElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeFile, element)
+}
@@ -0,0 +1,86 @@
+package goform
+
+import "io"
+
+// MultiFileElement accepts several files under one name. Validators
+// implementing FilesValidator check all files at once, other validators and
+// the filters run for every file.
+type MultiFileElement struct {
+	Element
+}
+
+func NewMultiFileElement(name string, label string, attributes []*Attribute, validators []ValidatorInterface, filters []FilterInterface) *MultiFileElement {
+	element := new(MultiFileElement)
+	element.Type = ElementTypeMultiFile
+	element.Name = name
+	element.Label = label
+	element.Attributes = attributes
+	element.Validators = validators
+	element.Filters = filters
+
+	return element
+}
+
+// SetFiles sets the files, the first file is also the File of the element.
+func (element *MultiFileElement) SetFiles(files []*File) {
+	element.Files = files
+	var file *File
+	if len(files) > 0 {
+		file = files[0]
+	}
+	element.Element.SetFile(file)
+}
+
+func (element *MultiFileElement) SetFile(file *File) {
+	if file == nil {
+		element.SetFiles(nil)
+		return
+	}
+	element.SetFiles([]*File{file})
+}
+
+func (element *MultiFileElement) IsValid() bool {
+	var errs []Message
+	for _, v := range element.Validators {
+		clearMessages(v)
+		valid := true
+		if fv, ok := v.(FilesValidator); ok {
+			valid = fv.IsValidFiles(element.Files)
+		} else if len(element.Files) == 0 {
+			v.SetFile(nil)
+			valid = v.IsValid()
+		} else {
+			for _, file := range element.Files {
+				v.SetFile(file)
+				if !v.IsValid() {
+					valid = false
+				}
+			}
+		}
+		if !valid {
+			errs = append(errs, v.GetMessages()...)
+		}
+	}
+	if len(errs) > 0 {
+		element.Errors = errs
+		return false
+	}
+	return true
+}
+
+func (element *MultiFileElement) ApplyFilters() {
+	for _, file := range element.Files {
+		for _, f := range element.Filters {
+			f.SetFile(file)
+			f.Apply()
+		}
+	}
+}
+
+func (element *MultiFileElement) Render() string {
+	return renderTemplate(ElementTypeFile, element)
+}
+
+func (element *MultiFileElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeFile, element)
+}
@@ -1,5 +1,7 @@
 package goform
 
+import "io"
+
 type HiddenElement struct {
 	Element
 }
@@ -18,3 +20,7 @@ func NewHiddenElement(name string, attributes []*Attribute, validators []Validat
 func (element *HiddenElement) Render() string {
 	return renderTemplate(ElementTypeHidden, element)
 }
+
+func (element *HiddenElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeHidden, element)
+}
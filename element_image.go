@@ -1,5 +1,7 @@
 package goform
 
+import "io"
+
 type ImageElement struct {
 	Element
 }
@@ -17,3 +19,7 @@ func NewImageElement(name string, label string, attributes []*Attribute) *ImageE
 func (element *ImageElement) Render() string {
 	return renderTemplate(ElementTypeImage, element)
 }
+
+func (element *ImageElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeImage, element)
+}
@@ -0,0 +1,22 @@
+package goform
+
+import "io"
+
+type MonthElement struct {
+	TemporalElement
+}
+
+func NewMonthElement(name string, label string, attributes []*Attribute, validators []ValidatorInterface, filters []FilterInterface) *MonthElement {
+	element := new(MonthElement)
+	newTemporalElement(&element.TemporalElement, ElementTypeMonth, LayoutMonth, name, label, attributes, validators, filters)
+
+	return element
+}
+
+func (element *MonthElement) Render() string {
+	return renderTemplate(ElementTypeText, element)
+}
+
+func (element *MonthElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeText, element)
+}
@@ -1,5 +1,7 @@
 package goform
 
+import "io"
+
 type NumberElement struct {
 	Element
 }
@@ -19,3 +21,7 @@ func NewNumberElement(name string, label string, attributes []*Attribute, valida
 func (element *NumberElement) Render() string {
 	return renderTemplate(ElementTypeText, element)
 }
+
+func (element *NumberElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeText, element)
+}
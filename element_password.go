@@ -1,5 +1,7 @@
 package goform
 
+import "io"
+
 type PasswordElement struct {
 	Element
 }
@@ -19,3 +21,7 @@ func NewPasswordElement(name string, label string, attributes []*Attribute, vali
 func (element *PasswordElement) Render() string {
 	return renderTemplate(ElementTypeText, element)
 }
+
+func (element *PasswordElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeText, element)
+}
@@ -1,5 +1,7 @@
 package goform
 
+import "io"
+
 type RadioElement struct {
 	Element
 }
@@ -20,3 +22,7 @@ func NewRadioElement(name string, label string, attributes []*Attribute, valueOp
 func (element *RadioElement) Render() string {
 	return renderTemplate(ElementTypeRadio, element)
 }
+
+func (element *RadioElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeRadio, element)
+}
@@ -1,5 +1,7 @@
 package goform
 
+import "io"
+
 type SearchElement struct {
 	Element
 }
@@ -19,3 +21,7 @@ func NewSearchElement(name string, label string, attributes []*Attribute, valida
 func (element *SearchElement) Render() string {
 	return renderTemplate(ElementTypeText, element)
 }
+
+func (element *SearchElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeText, element)
+}
@@ -1,5 +1,7 @@
 package goform
 
+import "io"
+
 type SelectElement struct {
 	Element
 }
@@ -20,3 +22,7 @@ func NewSelectElement(name string, label string, attributes []*Attribute, valueO
 func (element *SelectElement) Render() string {
 	return renderTemplate(ElementTypeSelect, element)
 }
+
+func (element *SelectElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeSelect, element)
+}
@@ -0,0 +1,28 @@
+package goform
+
+import "io"
+
+type MultiSelectElement struct {
+	Element
+}
+
+func NewMultiSelectElement(name string, label string, attributes []*Attribute, valueOptions []*ValueOption, validators []ValidatorInterface, filters []FilterInterface) *MultiSelectElement {
+	element := new(MultiSelectElement)
+	element.Type = ElementTypeMultiSelect
+	element.Name = name
+	element.Label = label
+	element.Attributes = attributes
+	element.ValueOptions = valueOptions
+	element.Validators = validators
+	element.Filters = filters
+
+	return element
+}
+
+func (element *MultiSelectElement) Render() string {
+	return renderTemplate(ElementTypeSelect, element)
+}
+
+func (element *MultiSelectElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeSelect, element)
+}
@@ -0,0 +1,127 @@
+package goform
+
+import (
+	"html/template"
+	"io"
+	"reflect"
+)
+
+// SubFormElement nests a whole form under the name of the element, the element
+// "city" of the sub form "address" is named "address[city]". It binds,
+// validates and maps the nested form into a nested struct or pointer to struct.
+type SubFormElement struct {
+	Element
+	Form *Form
+}
+
+func NewSubFormElement(name string, label string, attributes []*Attribute, form *Form) *SubFormElement {
+	element := new(SubFormElement)
+	element.Type = ElementTypeSubForm
+	element.Name = name
+	element.Label = label
+	element.Attributes = attributes
+	element.Form = form
+	form.setNamePrefix(name)
+
+	return element
+}
+
+func (element *SubFormElement) GetForm() *Form {
+	return element.Form
+}
+
+func (element *SubFormElement) IsMultipart() bool {
+	return element.Form.IsMultipart()
+}
+
+// mapTo maps the sub form into a struct or a pointer to struct, which is
+// allocated when nil unless the sub form is empty.
+func (element *SubFormElement) mapTo(field reflect.Value, path string, chain converterChain) FieldErrors {
+	if field.Kind() == reflect.Ptr && field.Type().Elem().Kind() == reflect.Struct {
+		if field.IsNil() {
+			if element.Form.isEmpty() {
+				return nil
+			}
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		field = field.Elem()
+	}
+	if field.Kind() != reflect.Struct {
+		return FieldErrors{{Field: path, Element: element.Name, Err: ErrUnsupportedField}}
+	}
+	return element.Form.mapStruct(field, path+".", chain.with(element.Form))
+}
+
+// isEmpty reports whether no element of the form has a value, a file or a
+// row.
+func (form *Form) isEmpty() bool {
+	for _, e := range form.elements {
+		switch element := e.(type) {
+		case *SubFormElement:
+			if !element.Form.isEmpty() {
+				return false
+			}
+			continue
+		case *CollectionElement:
+			if len(element.Rows) > 0 {
+				return false
+			}
+			continue
+		}
+		if len(conditionValues(e)) > 0 || e.GetFile() != nil || len(e.GetFiles()) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsValid validates the sub form, errors are reported on its elements.
+func (element *SubFormElement) IsValid() bool {
+	return element.Form.validate()
+}
+
+func (element *SubFormElement) ApplyFilters() {
+	element.Form.ApplyFilters()
+}
+
+func (element *SubFormElement) SetName(name string) {
+	element.Name = name
+	element.Form.setNamePrefix(name)
+}
+
+func (element *SubFormElement) SetTheme(theme Theme) {
+	element.Element.SetTheme(theme)
+	element.Form.SetTheme(theme)
+}
+
+func (element *SubFormElement) SetThemeLoader(loader *ThemeLoader) {
+	element.Element.SetThemeLoader(loader)
+	element.Form.SetThemeLoader(loader)
+}
+
+func (element *SubFormElement) SetTemplateFunctions(templateFunctions map[string]interface{}) {
+	element.Element.SetTemplateFunctions(templateFunctions)
+	element.Form.SetTemplateFunctions(templateFunctions)
+}
+
+func (element *SubFormElement) SetTranslator(translator Translator) {
+	element.Element.SetTranslator(translator)
+	element.Form.SetTranslator(translator)
+}
+
+func (element *SubFormElement) SetNoValidate(noValidate bool) {
+	element.Element.SetNoValidate(noValidate)
+	element.Form.SetNoValidate(noValidate)
+}
+
+func (element *SubFormElement) RenderElements() (template.HTML, error) {
+	return element.Form.RenderElements()
+}
+
+func (element *SubFormElement) Render() string {
+	return renderTemplate(ElementTypeSubForm, element)
+}
+
+func (element *SubFormElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeSubForm, element)
+}
@@ -1,5 +1,7 @@
 package goform
 
+import "io"
+
 type SubmitElement struct {
 	Element
 }
@@ -17,3 +19,7 @@ func NewSubmitElement(name string, label string, attributes []*Attribute) *Submi
 func (element *SubmitElement) Render() string {
 	return renderTemplate(ElementTypeSubmit, element)
 }
+
+func (element *SubmitElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeSubmit, element)
+}
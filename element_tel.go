@@ -1,5 +1,7 @@
 package goform
 
+import "io"
+
 type TelElement struct {
 	Element
 }
@@ -19,3 +21,7 @@ func NewTelElement(name string, label string, attributes []*Attribute, validator
 func (element *TelElement) Render() string {
 	return renderTemplate(ElementTypeText, element)
 }
+
+func (element *TelElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeText, element)
+}
@@ -0,0 +1,145 @@
+package goform
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Layouts of the values submitted by the HTML5 date and time inputs. Go has no
+// layout for ISO weeks, LayoutWeek is handled by ParseTime and FormatTime.
+const (
+	LayoutDate          = "2006-01-02"
+	LayoutTime          = "15:04"
+	LayoutDateTimeLocal = "2006-01-02T15:04"
+	LayoutMonth         = "2006-01"
+	LayoutWeek          = "2006-W01"
+)
+
+var (
+	ErrInvalidWeek = errors.New("Value is not a valid week")
+)
+
+type TemporalElementInterface interface {
+	ElementInterface
+	GetLayout() string
+	GetLocation() *time.Location
+	GetTime() (time.Time, error)
+	SetTime(t time.Time)
+}
+
+// TemporalElement is the base of the date and time elements. Its value is
+// parsed and formatted with Layout in Location, which defaults to UTC.
+type TemporalElement struct {
+	Element
+	Layout   string
+	Location *time.Location
+}
+
+func (element *TemporalElement) GetLayout() string {
+	return element.Layout
+}
+
+func (element *TemporalElement) SetLayout(layout string) {
+	element.Layout = layout
+}
+
+func (element *TemporalElement) GetLocation() *time.Location {
+	if element.Location == nil {
+		return time.UTC
+	}
+	return element.Location
+}
+
+func (element *TemporalElement) SetLocation(location *time.Location) {
+	element.Location = location
+}
+
+// GetTime parses the value of the element, an empty value is the zero time.
+func (element *TemporalElement) GetTime() (time.Time, error) {
+	if element.Value == "" {
+		return time.Time{}, nil
+	}
+	return ParseTime(element.Layout, element.Value, element.GetLocation())
+}
+
+// SetTime sets the value of the element, the zero time clears it.
+func (element *TemporalElement) SetTime(t time.Time) {
+	if t.IsZero() {
+		element.SetValue("")
+		return
+	}
+	element.SetValue(FormatTime(element.Layout, t.In(element.GetLocation())))
+}
+
+func (element *TemporalElement) IsValid() bool {
+	element.applyLayout()
+	return element.Element.IsValid()
+}
+
+func (element *TemporalElement) GetConstraintAttributes() []*Attribute {
+	element.applyLayout()
+	return element.Element.GetConstraintAttributes()
+}
+
+// applyLayout hands the layout of the element to the validators comparing
+// times, such as MinDateValidator.
+func (element *TemporalElement) applyLayout() {
+	for _, v := range element.Validators {
+		if v, ok := v.(layoutValidator); ok {
+			v.setLayout(element.Layout, element.GetLocation())
+		}
+	}
+}
+
+type layoutValidator interface {
+	setLayout(layout string, location *time.Location)
+}
+
+// ParseTime parses value with layout in location. LayoutWeek values are
+// parsed to the monday of the week, the time inputs may add seconds to their
+// value which are accepted as well.
+func ParseTime(layout string, value string, location *time.Location) (time.Time, error) {
+	if location == nil {
+		location = time.UTC
+	}
+	if layout == LayoutWeek {
+		var year, week int
+		if _, err := fmt.Sscanf(value, "%4d-W%2d", &year, &week); err != nil || len(value) != len("2006-W01") || week < 1 || week > 53 {
+			return time.Time{}, ErrInvalidWeek
+		}
+		// January 4th is always in the first ISO week
+		t := time.Date(year, time.January, 4, 0, 0, 0, 0, location)
+		t = t.AddDate(0, 0, -((int(t.Weekday())+6)%7)+(week-1)*7)
+		if y, w := t.ISOWeek(); y != year || w != week {
+			return time.Time{}, ErrInvalidWeek
+		}
+		return t, nil
+	}
+	t, err := time.ParseInLocation(layout, value, location)
+	if err != nil && (layout == LayoutTime || layout == LayoutDateTimeLocal) {
+		if t, err := time.ParseInLocation(layout+":05", value, location); err == nil {
+			return t, nil
+		}
+	}
+	return t, err
+}
+
+func FormatTime(layout string, t time.Time) string {
+	if layout == LayoutWeek {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	}
+	return t.Format(layout)
+}
+
+func newTemporalElement(element *TemporalElement, typ ElementType, layout string, name string, label string, attributes []*Attribute, validators []ValidatorInterface, filters []FilterInterface) {
+	element.Type = typ
+	element.Layout = layout
+	element.Name = name
+	element.Label = label
+	element.Attributes = attributes
+	element.Validators = validators
+	element.Filters = filters
+	element.applyLayout()
+}
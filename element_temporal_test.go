@@ -0,0 +1,198 @@
+package goform
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeWeek(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+		err   bool
+	}{
+		// January 4th 2010 is a monday, week 1 starts on it
+		{"2010-W01", time.Date(2010, time.January, 4, 0, 0, 0, 0, time.UTC), false},
+		// January 4th 2009 is a sunday, week 1 starts in 2008
+		{"2009-W01", time.Date(2008, time.December, 29, 0, 0, 0, 0, time.UTC), false},
+		{"2026-W01", time.Date(2025, time.December, 29, 0, 0, 0, 0, time.UTC), false},
+		{"2026-W42", time.Date(2026, time.October, 12, 0, 0, 0, 0, time.UTC), false},
+		{"2020-W53", time.Date(2020, time.December, 28, 0, 0, 0, 0, time.UTC), false},
+		{"2015-W53", time.Date(2015, time.December, 28, 0, 0, 0, 0, time.UTC), false},
+		{"2021-W53", time.Time{}, true},
+		{"2021-W00", time.Time{}, true},
+		{"2021-W54", time.Time{}, true},
+		{"2021-W1", time.Time{}, true},
+		{"2021-01", time.Time{}, true},
+		{"", time.Time{}, true},
+	}
+	for _, test := range tests {
+		got, err := ParseTime(LayoutWeek, test.value, nil)
+		if test.err {
+			if err != ErrInvalidWeek {
+				t.Errorf("ParseTime(%q) error = %v, want ErrInvalidWeek", test.value, err)
+			}
+			continue
+		}
+		if err != nil || !got.Equal(test.want) {
+			t.Errorf("ParseTime(%q) = %v, %v, want %v", test.value, got, err, test.want)
+		}
+		if formatted := FormatTime(LayoutWeek, got); formatted != test.value {
+			t.Errorf("FormatTime(%v) = %q, want %q", got, formatted, test.value)
+		}
+	}
+
+	location := time.FixedZone("TRT", 3*60*60)
+	got, err := ParseTime(LayoutWeek, "2020-W53", location)
+	if want := time.Date(2020, time.December, 28, 0, 0, 0, 0, location); err != nil || !got.Equal(want) {
+		t.Errorf("ParseTime in %s = %v, %v, want %v", location, got, err, want)
+	}
+}
+
+func TestFormatTimeWeek(t *testing.T) {
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{time.Date(2021, time.January, 3, 23, 59, 0, 0, time.UTC), "2020-W53"},
+		{time.Date(2021, time.January, 4, 0, 0, 0, 0, time.UTC), "2021-W01"},
+		{time.Date(2008, time.December, 31, 0, 0, 0, 0, time.UTC), "2009-W01"},
+		{time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC), "2026-W42"},
+	}
+	for _, test := range tests {
+		if got := FormatTime(LayoutWeek, test.t); got != test.want {
+			t.Errorf("FormatTime(%v) = %q, want %q", test.t, got, test.want)
+		}
+	}
+}
+
+func TestParseTimeSeconds(t *testing.T) {
+	tests := []struct {
+		layout string
+		value  string
+		want   time.Time
+		err    bool
+	}{
+		{LayoutTime, "09:30", time.Date(0, time.January, 1, 9, 30, 0, 0, time.UTC), false},
+		{LayoutTime, "09:30:15", time.Date(0, time.January, 1, 9, 30, 15, 0, time.UTC), false},
+		{LayoutTime, "09:30:15.5", time.Date(0, time.January, 1, 9, 30, 15, 5e8, time.UTC), false},
+		{LayoutTime, "09:30:15:00", time.Time{}, true},
+		{LayoutDateTimeLocal, "2026-10-18T09:30", time.Date(2026, time.October, 18, 9, 30, 0, 0, time.UTC), false},
+		{LayoutDateTimeLocal, "2026-10-18T09:30:15", time.Date(2026, time.October, 18, 9, 30, 15, 0, time.UTC), false},
+		{LayoutDate, "2026-10-18:15", time.Time{}, true},
+		{LayoutMonth, "2026-10:15", time.Time{}, true},
+	}
+	for _, test := range tests {
+		got, err := ParseTime(test.layout, test.value, nil)
+		if test.err {
+			if err == nil {
+				t.Errorf("ParseTime(%q, %q) = %v, want an error", test.layout, test.value, got)
+			}
+			continue
+		}
+		if err != nil || !got.Equal(test.want) {
+			t.Errorf("ParseTime(%q, %q) = %v, %v, want %v", test.layout, test.value, got, err, test.want)
+		}
+	}
+}
+
+func TestDateValidatorsLayout(t *testing.T) {
+	location := time.FixedZone("TRT", 3*60*60)
+	tests := []struct {
+		name    string
+		element func(validators []ValidatorInterface) TemporalElementInterface
+		min     time.Time
+		max     time.Time
+		value   string
+		valid   bool
+		message string
+	}{
+		{
+			name: "week",
+			element: func(v []ValidatorInterface) TemporalElementInterface {
+				return NewWeekElement("week", "Week", nil, v, nil)
+			},
+			min:   time.Date(2020, time.December, 28, 0, 0, 0, 0, time.UTC),
+			max:   time.Date(2021, time.January, 10, 0, 0, 0, 0, time.UTC),
+			value: "2020-W53",
+			valid: true,
+		},
+		{
+			name: "week before min",
+			element: func(v []ValidatorInterface) TemporalElementInterface {
+				return NewWeekElement("week", "Week", nil, v, nil)
+			},
+			min:     time.Date(2020, time.December, 28, 0, 0, 0, 0, time.UTC),
+			max:     time.Date(2021, time.January, 10, 0, 0, 0, 0, time.UTC),
+			value:   "2020-W52",
+			message: "Value must be after than 2020-W53",
+		},
+		{
+			name: "time with seconds",
+			element: func(v []ValidatorInterface) TemporalElementInterface {
+				return NewTimeElement("time", "Time", nil, v, nil)
+			},
+			min:     time.Date(0, time.January, 1, 9, 0, 0, 0, time.UTC),
+			max:     time.Date(0, time.January, 1, 17, 0, 0, 0, time.UTC),
+			value:   "17:00:01",
+			message: "Value must be before than 17:00",
+		},
+		{
+			name: "month",
+			element: func(v []ValidatorInterface) TemporalElementInterface {
+				return NewMonthElement("month", "Month", nil, v, nil)
+			},
+			min:   time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+			max:   time.Date(2026, time.December, 1, 0, 0, 0, 0, time.UTC),
+			value: "2026-10",
+			valid: true,
+		},
+		{
+			// 14:30 in TRT is 11:30 UTC, it would be after max in UTC
+			name: "location",
+			element: func(v []ValidatorInterface) TemporalElementInterface {
+				element := NewDateTimeLocalElement("at", "At", nil, v, nil)
+				element.SetLocation(location)
+				return element
+			},
+			min:   time.Date(2026, time.October, 18, 6, 0, 0, 0, time.UTC),
+			max:   time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC),
+			value: "2026-10-18T14:30",
+			valid: true,
+		},
+		{
+			name: "location after max",
+			element: func(v []ValidatorInterface) TemporalElementInterface {
+				element := NewDateTimeLocalElement("at", "At", nil, v, nil)
+				element.SetLocation(location)
+				return element
+			},
+			min:     time.Date(2026, time.October, 18, 6, 0, 0, 0, time.UTC),
+			max:     time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC),
+			value:   "2026-10-18T15:01",
+			message: "Value must be before than 2026-10-18T15:00",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			element := test.element([]ValidatorInterface{&MinDateValidator{Min: test.min}, &MaxDateValidator{Max: test.max}})
+			element.SetValue(test.value)
+			if valid := element.IsValid(); valid != test.valid {
+				t.Errorf("IsValid() = %v, want %v, errors %v", valid, test.valid, element.GetErrorMessages())
+			}
+			if messages := element.GetErrorMessages(); test.message != "" && (len(messages) != 1 || messages[0] != test.message) {
+				t.Errorf("errors = %v, want %q", messages, test.message)
+			}
+
+			min := FormatTime(element.GetLayout(), test.min.In(element.GetLocation()))
+			max := FormatTime(element.GetLayout(), test.max.In(element.GetLocation()))
+			constraints := map[string]string{}
+			for _, attribute := range element.GetConstraintAttributes() {
+				constraints[attribute.Key] = attribute.Value
+			}
+			if constraints["min"] != min || constraints["max"] != max {
+				t.Errorf("constraints = %v, want min %s and max %s", constraints, min, max)
+			}
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package goform
 
+import "io"
+
 type TextElement struct {
 	Element
 }
@@ -19,3 +21,7 @@ func NewTextElement(name string, label string, attributes []*Attribute, validato
 func (element *TextElement) Render() string {
 	return renderTemplate(ElementTypeText, element)
 }
+
+func (element *TextElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeText, element)
+}
@@ -1,5 +1,7 @@
 package goform
 
+import "io"
+
 type TextareaElement struct {
 	Element
 }
@@ -19,3 +21,7 @@ func NewTextareaElement(name string, label string, attributes []*Attribute, vali
 func (element *TextareaElement) Render() string {
 	return renderTemplate(ElementTypeTextarea, element)
 }
+
+func (element *TextareaElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeTextarea, element)
+}
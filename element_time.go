@@ -0,0 +1,22 @@
+package goform
+
+import "io"
+
+type TimeElement struct {
+	TemporalElement
+}
+
+func NewTimeElement(name string, label string, attributes []*Attribute, validators []ValidatorInterface, filters []FilterInterface) *TimeElement {
+	element := new(TimeElement)
+	newTemporalElement(&element.TemporalElement, ElementTypeTime, LayoutTime, name, label, attributes, validators, filters)
+
+	return element
+}
+
+func (element *TimeElement) Render() string {
+	return renderTemplate(ElementTypeText, element)
+}
+
+func (element *TimeElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeText, element)
+}
@@ -0,0 +1,22 @@
+package goform
+
+import "io"
+
+type WeekElement struct {
+	TemporalElement
+}
+
+func NewWeekElement(name string, label string, attributes []*Attribute, validators []ValidatorInterface, filters []FilterInterface) *WeekElement {
+	element := new(WeekElement)
+	newTemporalElement(&element.TemporalElement, ElementTypeWeek, LayoutWeek, name, label, attributes, validators, filters)
+
+	return element
+}
+
+func (element *WeekElement) Render() string {
+	return renderTemplate(ElementTypeText, element)
+}
+
+func (element *WeekElement) RenderTo(w io.Writer) error {
+	return renderTemplateTo(w, ElementTypeText, element)
+}
@@ -1,15 +1,18 @@
 package goform
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"html/template"
+	"io"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"net/url"
 	"path/filepath"
 	"reflect"
 	"regexp"
-	"strconv"
 	"strings"
 	"time"
 )
@@ -22,6 +25,18 @@ var (
 type FormInterface interface {
 	GetAction() string
 	SetAction(theme string)
+	GetMethod() string
+	SetMethod(method string)
+	GetId() string
+	SetId(id string)
+	AddAttribute(attribute *Attribute)
+	HasAttribute(key string) bool
+	GetAttribute(key string) (*Attribute, error)
+	SetAttribute(key string, value string)
+	GetAttributes() []*Attribute
+	IsMultipart() bool
+	IsNoValidate() bool
+	SetNoValidate(noValidate bool)
 	Has(key string) bool
 	Get(key string) (ElementInterface, error)
 	Add(element ElementInterface)
@@ -31,30 +46,52 @@ type FormInterface interface {
 	Prepend(...ElementInterface)
 	GetTheme() Theme
 	SetTheme(theme Theme)
+	SetThemeLoader(loader *ThemeLoader)
 	SetTemplateFunctions(templateFunctions map[string]interface{})
+	GetTranslator() Translator
+	SetTranslator(translator Translator)
 	HasError() bool
 	SetError(bool)
+	AddValidator(validator FormValidator)
+	GetValidators() []FormValidator
+	AddFormError(message string, args []interface{})
+	GetFormErrors() []Message
+	GetFormErrorMessages() []string
 	BuildQuery() string
 	Remove(key string) error
 	IsValid() bool
-	MapTo(model interface{})
+	RegisterConverter(v interface{}, converter Converter)
+	MapTo(model interface{}) error
 	BindFromRequest(req *http.Request)
+	BindFromJSON(r io.Reader) error
 	BindFromInterface(i interface{})
 
 	Render() string
+	RenderTo(w io.Writer) error
 }
 
 type Form struct {
 	action            string
+	method            string
+	id                string
+	attributes        []*Attribute
 	elements          []ElementInterface
 	hasError          bool
 	theme             Theme
+	themeLoader       *ThemeLoader
 	templateFunctions map[string]interface{}
+	translator        Translator
+	noValidate        bool
+	namePrefix        string
+	validators        []FormValidator
+	errors            []Message
+	converters        map[reflect.Type]Converter
 }
 
 func NewGoForm() *Form {
 	return &Form{
-		theme: ThemeBootstrap4Textual,
+		method: "post",
+		theme:  ThemeBootstrap4Textual,
 	}
 }
 
@@ -66,6 +103,86 @@ func (form *Form) SetAction(action string) {
 	form.action = action
 }
 
+func (form *Form) GetMethod() string {
+	return form.method
+}
+
+func (form *Form) SetMethod(method string) {
+	form.method = method
+}
+
+func (form *Form) GetId() string {
+	return form.id
+}
+
+func (form *Form) SetId(id string) {
+	form.id = id
+}
+
+func (form *Form) AddAttribute(attribute *Attribute) {
+	form.attributes = append(form.attributes, attribute)
+}
+
+func (form *Form) HasAttribute(key string) bool {
+	for _, attribute := range form.attributes {
+		if key == attribute.Key {
+			return true
+		}
+	}
+	return false
+}
+
+func (form *Form) GetAttribute(key string) (*Attribute, error) {
+	for _, attribute := range form.attributes {
+		if key == attribute.Key {
+			return attribute, nil
+		}
+	}
+	return nil, ErrAttributeNotFound
+}
+
+func (form *Form) SetAttribute(key string, value string) {
+	for idx, attribute := range form.attributes {
+		if attribute.Key == key {
+			form.attributes[idx] = &Attribute{Key: key, Value: value}
+			return
+		}
+	}
+	form.AddAttribute(&Attribute{Key: key, Value: value})
+}
+
+func (form *Form) GetAttributes() []*Attribute {
+	return form.attributes
+}
+
+// IsMultipart reports whether the form holds a file element and therefore has
+// to be submitted as multipart/form-data.
+func (form *Form) IsMultipart() bool {
+	for _, e := range form.elements {
+		if e.GetType() == ElementTypeFile || e.GetType() == ElementTypeMultiFile {
+			return true
+		}
+		if e, ok := e.(interface{ IsMultipart() bool }); ok && e.IsMultipart() {
+			return true
+		}
+	}
+	return false
+}
+
+func (form *Form) IsNoValidate() bool {
+	return form.noValidate
+}
+
+// SetNoValidate turns off browser validation, the form tag gets the novalidate
+// attribute and elements no longer derive constraint attributes from their
+// validators.
+func (form *Form) SetNoValidate(noValidate bool) {
+	for _, e := range form.GetElements() {
+		e.SetNoValidate(noValidate)
+	}
+	form.noValidate = noValidate
+}
+
 func (form *Form) GetElements() []ElementInterface {
 	return form.elements
 }
@@ -73,6 +190,9 @@ func (form *Form) GetElements() []ElementInterface {
 func (form *Form) SetElements(elements []ElementInterface) {
 	for _, e := range elements {
 		e.SetTheme(form.theme)
+		e.SetThemeLoader(form.themeLoader)
+		e.SetTranslator(form.translator)
+		e.SetNoValidate(form.noValidate)
 	}
 	form.elements = elements
 }
@@ -96,6 +216,15 @@ func (form *Form) SetTheme(theme Theme) {
 	form.theme = theme
 }
 
+// SetThemeLoader renders the form and its elements with the theme of the
+// loader, in reload mode changed theme files are picked up on the next render.
+func (form *Form) SetThemeLoader(loader *ThemeLoader) {
+	for _, e := range form.GetElements() {
+		e.SetThemeLoader(loader)
+	}
+	form.themeLoader = loader
+}
+
 func (form *Form) SetTemplateFunctions(templateFunctions map[string]interface{}) {
 	for _, e := range form.GetElements() {
 		e.SetTemplateFunctions(templateFunctions)
@@ -103,6 +232,19 @@ func (form *Form) SetTemplateFunctions(templateFunctions map[string]interface{})
 	form.templateFunctions = templateFunctions
 }
 
+func (form *Form) GetTranslator() Translator {
+	return form.translator
+}
+
+// SetTranslator sets the translator used to localize the validation messages
+// of every element of the form.
+func (form *Form) SetTranslator(translator Translator) {
+	for _, e := range form.GetElements() {
+		e.SetTranslator(translator)
+	}
+	form.translator = translator
+}
+
 func (form *Form) HasError() bool {
 	return form.hasError
 }
@@ -111,9 +253,38 @@ func (form *Form) SetError(e bool) {
 	form.hasError = e
 }
 
+// AddValidator adds a validator which checks the form as a whole, such as a
+// rule involving several elements.
+func (form *Form) AddValidator(validator FormValidator) {
+	form.validators = append(form.validators, validator)
+}
+
+func (form *Form) GetValidators() []FormValidator {
+	return form.validators
+}
+
+// AddFormError adds an error which does not belong to an element, themes
+// render them in the formerrors block above the elements.
+func (form *Form) AddFormError(message string, args []interface{}) {
+	form.errors = append(form.errors, Message{Message: message, Args: args})
+	form.hasError = true
+}
+
+func (form *Form) GetFormErrors() []Message {
+	return form.errors
+}
+
+func (form *Form) GetFormErrorMessages() []string {
+	var messages []string
+	for _, e := range form.errors {
+		messages = append(messages, e.Format(form.translator))
+	}
+	return messages
+}
+
 func (form *Form) Has(key string) bool {
 	for _, e := range form.elements {
-		if strings.Replace(e.GetName(), "[]", "", -1) == key {
+		if k, ok := form.elementKey(e.GetName()); ok && k == key {
 			return true
 		}
 	}
@@ -122,23 +293,73 @@ func (form *Form) Has(key string) bool {
 
 func (form *Form) Get(key string) (ElementInterface, error) {
 	for _, e := range form.elements {
-		if strings.Replace(e.GetName(), "[]", "", -1) == key {
+		if k, ok := form.elementKey(e.GetName()); ok && k == key {
 			return e, nil
 		}
 	}
 	return nil, ErrElementNotFound
 }
 
+// elementKey is the name an element is looked up by, the name without the []
+// of multi valued elements and without the prefix of a nested form, so the
+// element "items[0][sku]" of the form prefixed "items[0]" is "sku". A name
+// outside the prefix is not a name of the form, e.g. "sku" of that form.
+func (form *Form) elementKey(name string) (string, bool) {
+	name = strings.Replace(name, "[]", "", -1)
+	if form.namePrefix == "" {
+		return name, true
+	}
+	if strings.HasPrefix(name, form.namePrefix+"[") && strings.HasSuffix(name, "]") {
+		return name[len(form.namePrefix)+1 : len(name)-1], true
+	}
+	return name, false
+}
+
+// setNamePrefix nests the names of the elements under prefix, which is how
+// the rows of a collection are named.
+func (form *Form) setNamePrefix(prefix string) {
+	for _, e := range form.elements {
+		key, _ := form.elementKey(e.GetName())
+		e.SetName(prefixName(prefix, key))
+	}
+	form.namePrefix = prefix
+}
+
+// nestedValues returns the values named under prefix, e.g. "address[city]" for
+// the prefix "address", which are the values of a sub form.
+func nestedValues[V any](values map[string]V, prefix string) map[string]V {
+	nested := map[string]V{}
+	for name, value := range values {
+		if strings.HasPrefix(name, prefix+"[") {
+			nested[name] = value
+		}
+	}
+	return nested
+}
+
+func prefixName(prefix string, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "[" + name + "]"
+}
+
 func (form *Form) Add(element ElementInterface) {
+	if form.namePrefix != "" {
+		element.SetName(prefixName(form.namePrefix, element.GetName()))
+	}
 	element.SetTheme(form.theme)
 	element.SetTemplateFunctions(form.templateFunctions)
+	element.SetThemeLoader(form.themeLoader)
+	element.SetTranslator(form.translator)
+	element.SetNoValidate(form.noValidate)
 	form.elements = append(form.elements, element)
 }
 
 func (form *Form) BuildQuery() string {
 	var q string
 	for _, element := range form.elements {
-		if element.GetType() == ElementTypeMultiCheckbox {
+		if element.IsMultiple() {
 			for _, v := range element.GetValues() {
 				q += element.GetName() + "[]=" + v + "&"
 			}
@@ -160,7 +381,27 @@ func (form *Form) Remove(key string) error {
 }
 
 func (form *Form) IsValid() bool {
+	if !form.validate() {
+		return false
+	}
+	for _, e := range form.elements {
+		e.ApplyFilters()
+	}
+	return true
+}
+
+// validate runs the validators of the elements and of the form without
+// applying the filters. The errors of an earlier validation are cleared.
+func (form *Form) validate() bool {
+	form.hasError = false
+	form.errors = nil
+	for _, e := range form.GetElements() {
+		e.ClearErrors()
+	}
 	for _, e := range form.GetElements() {
+		if !form.IsActive(e) {
+			continue
+		}
 		for _, v := range e.GetValidators() {
 			if v, ok := v.(*IdenticalValidator); ok {
 				if form.Has(v.ElementName) {
@@ -172,13 +413,37 @@ func (form *Form) IsValid() bool {
 			form.hasError = true
 		}
 	}
-	if form.hasError {
-		return false
+	for _, v := range form.validators {
+		if !v.IsValid(form) {
+			form.hasError = true
+		}
 	}
+	return !form.hasError
+}
+
+// GetErrorMessages returns the error messages of the elements keyed by the full
+// name of the element, including the elements of collections and sub forms,
+// e.g. "address[city]".
+func (form *Form) GetErrorMessages() map[string][]string {
+	messages := map[string][]string{}
+	form.collectErrorMessages(messages)
+	return messages
+}
+
+func (form *Form) collectErrorMessages(messages map[string][]string) {
 	for _, e := range form.elements {
-		e.ApplyFilters()
+		if errs := e.GetErrorMessages(); len(errs) > 0 {
+			messages[e.GetName()] = errs
+		}
+		switch e := e.(type) {
+		case *CollectionElement:
+			for _, row := range e.Rows {
+				row.collectErrorMessages(messages)
+			}
+		case *SubFormElement:
+			e.Form.collectErrorMessages(messages)
+		}
 	}
-	return true
 }
 
 func (form *Form) ApplyFilters() {
@@ -187,401 +452,228 @@ func (form *Form) ApplyFilters() {
 	}
 }
 
+// Render renders the whole form, the theme's form template wraps the elements
+// in a form tag carrying the action, method, id and attributes of the form.
+// If rendering fails it returns the error as an HTML comment, use RenderTo to
+// handle the error.
 func (form *Form) Render() string {
-	var h string
+	var buffer bytes.Buffer
+	if err := form.RenderTo(&buffer); err != nil {
+		return renderError(err)
+	}
+	return buffer.String()
+}
+
+func (form *Form) RenderTo(w io.Writer) error {
+	theme := form.theme
+	if form.themeLoader != nil {
+		var err error
+		if theme, err = form.themeLoader.Theme(); err != nil {
+			return err
+		}
+	}
+	return renderThemeTo(w, theme, form.templateFunctions, "form", form)
+}
+
+// RenderElements renders the elements without the surrounding form tag.
+func (form *Form) RenderElements() (template.HTML, error) {
+	var buffer bytes.Buffer
 	for _, e := range form.elements {
-		h += e.Render()
+		if err := e.RenderTo(&buffer); err != nil {
+			return "", err
+		}
 	}
-	return h
+	return template.HTML(buffer.String()), nil
 }
 
-func (form *Form) MapTo(model interface{}) {
-	if reflect.TypeOf(model).Kind() != reflect.Ptr {
-		panic("Argument should be specified pointer type.")
+// MapTo maps the values of the elements to the fields of the struct model
+// points to, an empty value sets a pointer field to nil. A value which cannot
+// be converted to the type of its field leaves the field unchanged and is
+// added to the errors of its element, the form has errors until it is
+// validated again. The failed fields are returned as FieldErrors.
+func (form *Form) MapTo(model interface{}) error {
+	value := reflect.ValueOf(model)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return ErrPointerExpected
+	}
+	if errs := form.mapStruct(value.Elem(), "", converterChain{form.converters}); len(errs) > 0 {
+		form.hasError = true
+		return errs
 	}
-	mType := reflect.TypeOf(model).Elem()
-	mValue := reflect.ValueOf(model).Elem()
+	return nil
+}
 
-	for i := 0; i < mValue.NumField(); i++ {
-		typeField := mType.Field(i)
-		tag := typeField.Tag.Get("goform")
-		//var structField string
-		if tag != "" {
-			tagOptions := strings.Split(tag, ";")
-			tag = tagOptions[0]
-			if len(tagOptions) > 1 {
-				//structField = tagOptions[1]
-			}
-		} else {
-			tag = underscore(typeField.Name)
+// mapStruct maps the elements to the fields of model, path is prepended to the
+// field names in the errors, e.g. "Address." for a sub form.
+func (form *Form) mapStruct(model reflect.Value, path string, chain converterChain) FieldErrors {
+	var errs FieldErrors
+	form.eachField(model, true, func(field ElementInterface, value reflect.Value, name string) {
+		// elements hidden by their conditions are left out
+		if !form.IsActive(field) {
+			return
 		}
+		errs = append(errs, mapField(field, value, path+name, chain)...)
+	})
+	return errs
+}
 
-		field, err := form.Get(strings.Replace(tag, "[]", "", -1))
-		if err != nil {
+// eachField calls fn for every field of the struct v the form has an element
+// for. The fields of embedded structs are promoted like in Go, nil embedded
+// pointers are allocated if allocate is set and skipped otherwise.
+func (form *Form) eachField(v reflect.Value, allocate bool, fn func(field ElementInterface, value reflect.Value, name string)) {
+	typ := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		typeField := typ.Field(i)
+		name, ok := elementName(typeField)
+		if !ok {
+			continue
+		}
+		value := v.Field(i)
+		if field, err := form.Get(name); err == nil && typeField.PkgPath == "" {
+			fn(field, value, typeField.Name)
 			continue
 		}
 
-		v := field.GetValue()
-		if field.GetType() == ElementTypeMultiCheckbox {
-			v = strings.Join(field.GetValues(), " ")
+		if !typeField.Anonymous || typeField.Tag.Get("goform") != "" {
+			continue
+		}
+		if value.Kind() == reflect.Ptr && value.Type().Elem().Kind() == reflect.Struct {
+			if value.IsNil() {
+				if !allocate || typeField.PkgPath != "" {
+					continue
+				}
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+			value = value.Elem()
 		}
+		if value.Kind() == reflect.Struct {
+			form.eachField(value, allocate, fn)
+		}
+	}
+}
 
-		workField := mValue.Field(i)
+// elementName returns the name of the element of a struct field, the first
+// part of its goform tag or the field name in snake case. Fields tagged "-"
+// have no element.
+func elementName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("goform")
+	if tag == "-" {
+		return "", false
+	}
+	name := strings.TrimSpace(strings.Split(tag, ";")[0])
+	if name == "" {
+		name = underscore(field.Name)
+	}
+	return strings.Replace(name, "[]", "", -1), true
+}
 
-		switch workField.Kind() {
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			value, err := strconv.Atoi(v)
-			if err != nil {
-				fmt.Printf("parsing error", v)
-			}
-			workField.SetInt(int64(value))
-		case reflect.Uint32:
-			value, err := strconv.Atoi(v)
-			if err != nil {
-				fmt.Printf("parsing error", v)
-			}
-			workField.SetUint(uint64(value))
-		case reflect.Float32, reflect.Float64:
-			value, err := strconv.ParseFloat(v, 64)
-			if err != nil {
-				fmt.Printf("parsing error", v)
-			}
-			workField.SetFloat(value)
-		case reflect.String:
-			workField.SetString(v)
-		case reflect.Slice:
-			workField.Set(reflect.ValueOf(strings.Fields(v)))
-		case reflect.Bool:
-			value, err := strconv.ParseBool(v)
-			if err != nil {
-				fmt.Println("parsing error", field.GetLabel(), v)
-			}
-			workField.SetBool(value)
-		case reflect.Struct:
-			switch workField.Type().String() {
-			case "time.Time":
-				layout := "2006-01-02"
-				t, err := time.Parse(layout, v)
+func (form *Form) BindFromPost(req *http.Request) {
+	if isJSONRequest(req) {
+		form.bindJSON(req.Body, req.RemoteAddr)
+		return
+	}
 
-				if err != nil {
-					fmt.Println("parsing error", v)
-				}
-				workField.Set(reflect.ValueOf(t))
-			case "goform.File":
-				if field.GetFile() != nil {
-					workField.Set(reflect.ValueOf(*field.GetFile()))
-				}
-			default:
-				vStruct := reflect.New(workField.Type())
-				if vStruct.Kind() == reflect.Ptr {
-					vStruct = vStruct.Elem()
-				}
+	form.bindValues(req.PostForm)
+	form.bindCaptchas(req.PostForm, req.RemoteAddr)
 
-				vStructFirstKind := vStruct.Field(0).Kind().String()
-
-				switch vStructFirstKind {
-				case "string":
-					vStruct.Field(0).Set(reflect.ValueOf(v))
-				case "uint32":
-					intV, err := strconv.Atoi(v)
-					if err != nil {
-						fmt.Println("an error occurred while str to uint32", vStructFirstKind)
-						continue
-					}
-					vStruct.Field(0).Set(reflect.ValueOf(uint32(intV)))
-				case "int64":
-					intV, err := strconv.Atoi(v)
-					if err != nil {
-						fmt.Println("an error occurred while str to int64", vStructFirstKind)
-						continue
-					}
-					vStruct.Field(0).Set(reflect.ValueOf(int64(intV)))
-				case "int32":
-					intV, err := strconv.Atoi(v)
-					if err != nil {
-						fmt.Println("an error occurred while str to int32", vStructFirstKind)
-						continue
-					}
-					vStruct.Field(0).Set(reflect.ValueOf(int32(intV)))
-				case "int16":
-					intV, err := strconv.Atoi(v)
-					if err != nil {
-						fmt.Println("an error occurred while str to int16", vStructFirstKind)
-						continue
-					}
-					vStruct.Field(0).Set(reflect.ValueOf(int16(intV)))
-				case "int8":
-					intV, err := strconv.Atoi(v)
-					if err != nil {
-						fmt.Println("an error occurred while str to int8", vStructFirstKind)
-						continue
-					}
-					vStruct.Field(0).Set(reflect.ValueOf(int8(intV)))
-				case "int":
-					intV, err := strconv.Atoi(v)
-					if err != nil {
-						fmt.Println("an error occurred while str to int", vStructFirstKind)
-						continue
-					}
-					vStruct.Field(0).Set(reflect.ValueOf(intV))
-				case "float64":
-					floatV, err := strconv.ParseFloat(v, 64)
-					if err != nil {
-						fmt.Println("an error occurred while str to float64", vStructFirstKind)
-						continue
-					}
-					vStruct.Field(0).Set(reflect.ValueOf(floatV))
-				case "float32":
-					floatV, err := strconv.ParseFloat(v, 32)
-					if err != nil {
-						fmt.Println("an error occurred while str to float32", vStructFirstKind)
-						continue
-					}
-					vStruct.Field(0).Set(reflect.ValueOf(floatV))
-				case "bool":
-					boolV, err := strconv.ParseBool(v)
-					if err != nil {
-						fmt.Println("an error occurred while str to bool", vStructFirstKind)
-						continue
-					}
-					vStruct.Field(0).Set(reflect.ValueOf(boolV))
-				default:
-					fmt.Println("unknown interface field kind", vStructFirstKind)
-				}
+	if req.Header.Get("Content-Type") != "" {
+		if strings.Fields(req.Header.Get("Content-Type"))[0] == "multipart/form-data;" {
+			req.ParseMultipartForm(0)
+			form.bindFiles(req.MultipartForm.File)
+		}
+	}
+}
 
-				workField.Set(vStruct)
-			}
-		case reflect.Ptr:
-			if v == "" && field.GetFile() == nil {
-				continue
-			}
+// BindFromRequest binds the values and files of req, JSON bodies are bound
+// like in BindFromJSON. A body which is not valid JSON binds nothing, use
+// BindFromJSON to get the error.
+func (form *Form) BindFromRequest(req *http.Request) {
+	if isJSONRequest(req) {
+		form.bindJSON(req.Body, req.RemoteAddr)
+		return
+	}
 
-			workFieldElem := reflect.New(workField.Type().Elem())
+	form.bindValues(req.Form)
+	form.bindCaptchas(req.Form, req.RemoteAddr)
 
-			switch workFieldElem.Elem().Kind() {
-			case reflect.Uint32:
-				value, err := strconv.Atoi(v)
-				if err != nil {
-					fmt.Printf("parsing error", v)
-				}
-				iVal := uint32(value)
-				workField.Set(reflect.ValueOf(&iVal))
-			case reflect.Int64:
-				value, err := strconv.Atoi(v)
-				if err != nil {
-					fmt.Printf("parsing error", v)
-				}
-				iVal := int64(value)
-				workField.Set(reflect.ValueOf(&iVal))
-			case reflect.Int32:
-				value, err := strconv.Atoi(v)
-				if err != nil {
-					fmt.Printf("parsing error", v)
-				}
-				iVal := int32(value)
-				workField.Set(reflect.ValueOf(&iVal))
-			case reflect.Int16:
-				value, err := strconv.Atoi(v)
-				if err != nil {
-					fmt.Printf("parsing error", v)
-				}
-				iVal := int16(value)
-				workField.Set(reflect.ValueOf(&iVal))
-			case reflect.Int8:
-				value, err := strconv.Atoi(v)
-				if err != nil {
-					fmt.Printf("parsing error", v)
-				}
-				iVal := int8(value)
-				workField.Set(reflect.ValueOf(&iVal))
-			case reflect.Int:
-				value, err := strconv.Atoi(v)
-				if err != nil {
-					fmt.Printf("parsing error", v)
-				}
-				workField.Set(reflect.ValueOf(&value))
-			case reflect.Float32, reflect.Float64:
-				value, err := strconv.ParseFloat(v, 64)
-				if err != nil {
-					fmt.Printf("parsing error", v)
-				}
-				workField.Set(reflect.ValueOf(&value))
-			case reflect.String:
-				if v != "" {
-					workField.Set(reflect.ValueOf(&v))
-				}
-			case reflect.Slice:
-				fields := strings.Fields(v)
-				workField.Set(reflect.ValueOf(&fields))
-			case reflect.Bool:
-				value, err := strconv.ParseBool(v)
-				if err != nil {
-					fmt.Printf("parsing error", v)
-				}
-				workField.Set(reflect.ValueOf(&value))
-			case reflect.Struct:
-				switch workField.Type().String() {
-				case "*time.Time":
-					layout := "2006-01-02"
-					t, err := time.Parse(layout, v)
-
-					if err != nil {
-						fmt.Println("parsing error", v)
-					}
-					workField.Set(reflect.ValueOf(&t))
-				case "*goform.File":
-					workField.Set(reflect.ValueOf(field.GetFile()))
-				default:
-					vStruct := reflect.New(workField.Type())
-					if vStruct.Kind() == reflect.Ptr {
-						vStruct = vStruct.Elem()
-					}
-
-					var vStructFirstField reflect.Value
-					if vStruct.IsNil() {
-						wFType := workField.Type().Elem()
-						vStruct = reflect.New(wFType)
-					}
-					vStructFirstField = vStruct.Elem().Field(0)
-					vStructFirstKind := vStructFirstField.Kind().String()
-
-					switch vStructFirstKind {
-					case "string":
-						vStruct.Field(0).Set(reflect.ValueOf(v))
-					case "uint32":
-						intV, err := strconv.Atoi(v)
-						if err != nil {
-							fmt.Println("an error occurred while str to uint32", vStructFirstKind)
-							continue
-						}
-						vStructFirstField.Set(reflect.ValueOf(uint32(intV)))
-					case "int64":
-						intV, err := strconv.Atoi(v)
-						if err != nil {
-							fmt.Println("an error occurred while str to int64", vStructFirstKind)
-							continue
-						}
-						vStructFirstField.Set(reflect.ValueOf(int64(intV)))
-					case "int32":
-						intV, err := strconv.Atoi(v)
-						if err != nil {
-							fmt.Println("an error occurred while str to int32", vStructFirstKind)
-							continue
-						}
-						vStructFirstField.Set(reflect.ValueOf(int32(intV)))
-					case "int16":
-						intV, err := strconv.Atoi(v)
-						if err != nil {
-							fmt.Println("an error occurred while str to int16", vStructFirstKind)
-							continue
-						}
-						vStructFirstField.Set(reflect.ValueOf(int16(intV)))
-					case "int8":
-						intV, err := strconv.Atoi(v)
-						if err != nil {
-							fmt.Println("an error occurred while str to int8", vStructFirstKind)
-							continue
-						}
-						vStructFirstField.Set(reflect.ValueOf(int8(intV)))
-					case "int":
-						intV, err := strconv.Atoi(v)
-						if err != nil {
-							fmt.Println("an error occurred while str to int", vStructFirstKind)
-							continue
-						}
-						vStructFirstField.Set(reflect.ValueOf(intV))
-					case "float64":
-						floatV, err := strconv.ParseFloat(v, 64)
-						if err != nil {
-							fmt.Println("an error occurred while str to float64", vStructFirstKind)
-							continue
-						}
-						vStructFirstField.Set(reflect.ValueOf(floatV))
-					case "float32":
-						floatV, err := strconv.ParseFloat(v, 32)
-						if err != nil {
-							fmt.Println("an error occurred while str to float32", vStructFirstKind)
-							continue
-						}
-						vStructFirstField.Set(reflect.ValueOf(floatV))
-					case "bool":
-						boolV, err := strconv.ParseBool(v)
-						if err != nil {
-							fmt.Println("an error occurred while str to bool", vStructFirstKind)
-							continue
-						}
-						vStructFirstField.Set(reflect.ValueOf(boolV))
-					default:
-						fmt.Println("unknown interface field kind", vStructFirstKind)
-					}
-
-					workField.Set(vStruct)
-				}
-			}
+	if req.Header.Get("Content-Type") != "" {
+		if strings.Fields(req.Header.Get("Content-Type"))[0] == "multipart/form-data;" {
+			req.ParseMultipartForm(0)
+			form.bindFiles(req.MultipartForm.File)
 		}
 	}
 }
 
-func (form *Form) BindFromPost(req *http.Request) {
-	for name, value := range req.PostForm {
-		field, err := form.Get(strings.Replace(name, "[]", "", -1))
-		if err != nil {
+// bindFiles binds the uploaded files, a multi file element keeps all files of
+// its name while other elements keep the last one.
+func (form *Form) bindFiles(headers map[string][]*multipart.FileHeader) {
+	for name, hdrs := range headers {
+		key, ok := form.elementKey(name)
+		if !ok {
 			continue
 		}
-		if field.GetType() == ElementTypeMultiCheckbox {
-			field.SetValues(value)
+		field, err := form.Get(key)
+		if err != nil {
 			continue
 		}
-		if field.GetType() == ElementTypeFile {
+		var files []*File
+		for _, hdr := range hdrs {
+			if hdr.Filename == "" {
+				continue
+			}
+			infile, err := hdr.Open()
+			if err != nil {
+				fmt.Println(err)
+				continue
+			}
+			fileParts := strings.Split(hdr.Filename, ".")
+			files = append(files, &File{
+				Headers:   hdr.Header,
+				Name:      hdr.Filename,
+				Extension: fileParts[len(fileParts)-1],
+				Size:      hdr.Size,
+				Binary:    infile,
+			})
+		}
+		if field.GetType() == ElementTypeMultiFile {
+			field.SetFiles(files)
 			continue
 		}
-		field.SetValue(value[0])
+		if len(files) > 0 {
+			field.SetFile(files[len(files)-1])
+		}
 	}
 
-	// handle situation when checkboxes has default value set to checked/true and the value is not reset because
-	// unchecked checkbox is not in the request
-	form.bindUncheckedCheckboxes(req.PostForm)
-
-	if req.Header.Get("Content-Type") != "" {
-		if strings.Fields(req.Header.Get("Content-Type"))[0] == "multipart/form-data;" {
-			req.ParseMultipartForm(0)
-			var err error
-			for name, headers := range req.MultipartForm.File {
-				if form.Has(name) {
-					field, _ := form.Get(name)
-					for _, hdr := range headers {
-						var infile multipart.File
-						if infile, err = hdr.Open(); nil != err {
-							fmt.Println(err)
-						}
-
-						if hdr.Filename != "" {
-							fileParts := strings.Split(hdr.Filename, ".")
-							field.SetFile(&File{
-								Headers:   hdr.Header,
-								Name:      hdr.Filename,
-								Extension: fileParts[len(fileParts)-1],
-								Binary:    infile,
-							})
-						}
-					}
-				}
-			}
+	for _, field := range form.GetElements() {
+		switch field := field.(type) {
+		case *CollectionElement:
+			field.bindFiles(headers)
+		case *SubFormElement:
+			field.Form.bindFiles(nestedValues(headers, field.Name))
 		}
 	}
 }
 
-func (form *Form) BindFromRequest(req *http.Request) {
-	for name, value := range req.Form {
-		field, err := form.Get(strings.Replace(name, "[]", "", -1))
+// bindValues binds the submitted values to the elements, collections and sub
+// forms bind the values named after them.
+func (form *Form) bindValues(val url.Values) {
+	for name, value := range val {
+		key, ok := form.elementKey(name)
+		if !ok {
+			continue
+		}
+		field, err := form.Get(key)
 		if err != nil {
 			continue
 		}
-		if field.GetType() == ElementTypeMultiCheckbox {
+		if field.GetType() == ElementTypeFile || field.GetType() == ElementTypeMultiFile {
+			continue
+		}
+		if field.IsMultiple() {
 			field.SetValues(value)
 			continue
 		}
-		if field.GetType() == ElementTypeFile {
+		if len(value) == 0 {
 			continue
 		}
 		field.SetValue(value[0])
@@ -589,33 +681,14 @@ func (form *Form) BindFromRequest(req *http.Request) {
 
 	// handle situation when checkboxes has default value set to checked/true and the value is not reset because
 	// unchecked checkbox is not in the request
-	form.bindUncheckedCheckboxes(req.Form)
+	form.bindUncheckedCheckboxes(val)
 
-	if req.Header.Get("Content-Type") != "" {
-		if strings.Fields(req.Header.Get("Content-Type"))[0] == "multipart/form-data;" {
-			req.ParseMultipartForm(0)
-			var err error
-			for name, headers := range req.MultipartForm.File {
-				if form.Has(name) {
-					field, _ := form.Get(name)
-					for _, hdr := range headers {
-						var infile multipart.File
-						if infile, err = hdr.Open(); nil != err {
-							fmt.Println(err)
-						}
-
-						if hdr.Filename != "" {
-							fileParts := strings.Split(hdr.Filename, ".")
-							field.SetFile(&File{
-								Headers:   hdr.Header,
-								Name:      hdr.Filename,
-								Extension: fileParts[len(fileParts)-1],
-								Binary:    infile,
-							})
-						}
-					}
-				}
-			}
+	for _, field := range form.GetElements() {
+		switch field := field.(type) {
+		case *CollectionElement:
+			field.bindValues(val)
+		case *SubFormElement:
+			field.Form.bindValues(nestedValues(val, field.Name))
 		}
 	}
 }
@@ -628,96 +701,197 @@ func (form *Form) bindUncheckedCheckboxes(val url.Values) {
 				field.SetValue("false")
 			}
 		}
+		if field.IsMultiple() {
+			name := strings.Replace(field.GetName(), "[]", "", -1)
+			_, ok := val[name]
+			_, okBrackets := val[name+"[]"]
+			if !ok && !okBrackets {
+				// nothing selected, clear the selection instead of keeping the defaults
+				field.SetValues([]string{})
+			}
+		}
+	}
+}
+
+// bindCaptchas hands the request to captcha elements, their providers read the
+// fields they need, which are not necessarily named after the element.
+func (form *Form) bindCaptchas(val url.Values, remoteAddr string) {
+	remoteIp, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		remoteIp = remoteAddr
 	}
+	form.setCaptchaResponses(CaptchaResponse{Values: val, RemoteIp: remoteIp})
 }
 
+// setCaptchaResponses hands the response to the captcha elements of the form,
+// its sub forms and collection rows.
+func (form *Form) setCaptchaResponses(response CaptchaResponse) {
+	for _, field := range form.GetElements() {
+		switch field := field.(type) {
+		case *CaptchaElement:
+			field.SetResponse(response)
+		case *CollectionElement:
+			for _, row := range field.Rows {
+				row.setCaptchaResponses(response)
+			}
+		case *SubFormElement:
+			field.Form.setCaptchaResponses(response)
+		}
+	}
+}
+
+// BindFromInterface sets the values of the elements from the fields of the
+// struct i or i points to, the fields are matched with the elements like in
+// MapTo, so a struct bound into a form maps back to an equal struct.
 func (form *Form) BindFromInterface(i interface{}) {
 	v := reflect.ValueOf(i)
-
-	rVal := reflect.TypeOf(i)
-	if rVal.Kind() == reflect.Ptr {
+	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
 	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	form.bindStruct(v, converterChain{form.converters})
+}
 
-	typ := v.Type()
+func (form *Form) bindStruct(v reflect.Value, chain converterChain) {
+	form.eachField(v, false, func(field ElementInterface, value reflect.Value, name string) {
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return
+			}
+			value = value.Elem()
+		}
+		bindField(field, value, chain)
+	})
+}
 
-	for i := 0; i < v.NumField(); i++ {
-		key := underscore(typ.Field(i).Name)
-		vField := v.Field(i)
+// bindField sets the value of an element from a field, fields which cannot be
+// formatted are left out.
+func bindField(field ElementInterface, vField reflect.Value, chain converterChain) {
+	switch element := field.(type) {
+	case *SubFormElement:
+		if vField.Kind() == reflect.Struct {
+			element.Form.bindStruct(vField, chain.with(element.Form))
+		}
+		return
+	case *CollectionElement:
+		if vField.Kind() == reflect.Slice {
+			element.bindSlice(vField, chain)
+		}
+		return
+	}
 
-		if vField.Kind() == reflect.Ptr {
-			if vField.IsNil() {
-				continue
+	typ := vField.Type()
+	if !chain.canFormat(typ) {
+		switch {
+		case typ == fileType:
+			if file := vField.Interface().(File); file.Name != "" || file.Location != "" {
+				field.SetFile(&file)
+			}
+			return
+		case typ == timeType || isTimestamp(typ):
+			t, ok := vField.Interface().(time.Time)
+			if !ok {
+				t = timestampTime(vField)
+			}
+			if temporal, ok := field.(TemporalElementInterface); ok {
+				temporal.SetTime(t)
+				return
+			}
+			field.SetValue(t.Format(LayoutDate))
+			return
+		case typ.Kind() == reflect.Slice:
+			if field.GetType() == ElementTypeMultiFile {
+				field.SetFiles(filesFromSlice(vField))
+				return
+			}
+			values := make([]string, 0, vField.Len())
+			for j := 0; j < vField.Len(); j++ {
+				value, err := chain.format(vField.Index(j))
+				if err != nil {
+					return
+				}
+				values = append(values, value)
+			}
+			if field.IsMultiple() {
+				field.SetValues(values)
+				return
 			}
-			vField = vField.Elem()
+			field.SetValue(strings.Join(values, " "))
+			return
 		}
-		val := vField.Interface()
+	}
 
-		if form.Has(key) {
-			field, err := form.Get(key)
-			if err != nil {
-				fmt.Println("field not found", key)
-				continue
-			}
+	value, err := chain.format(vField)
+	if err != nil {
+		return
+	}
+	switch {
+	case field.IsMultiple():
+		field.SetValues(strings.Fields(value))
+	case field.GetType() == ElementTypeFile:
+		if value != "" {
+			_, fileName := filepath.Split(value)
+			field.SetFile(&File{
+				Location: value,
+				Name:     fileName,
+			})
+		}
+	default:
+		field.SetValue(value)
+	}
+}
 
-			vType := reflect.ValueOf(val).Kind().String()
+// fileSliceValue converts the files of a multi file element to a []File or
+// []*File.
+func fileSliceValue(files []*File, typ reflect.Type) (reflect.Value, error) {
+	if len(files) == 0 {
+		return reflect.Zero(typ), nil
+	}
+	slice := reflect.MakeSlice(typ, 0, len(files))
+	for _, file := range files {
+		switch typ.Elem() {
+		case reflect.TypeOf(File{}):
+			slice = reflect.Append(slice, reflect.ValueOf(*file))
+		case reflect.TypeOf(&File{}):
+			slice = reflect.Append(slice, reflect.ValueOf(file))
+		default:
+			return slice, ErrUnsupportedField
+		}
+	}
+	return slice, nil
+}
 
-			switch vType {
-			case "string":
-				if field.GetType() == ElementTypeMultiCheckbox {
-					field.SetValues(strings.Fields(val.(string)))
-					continue
-				}
-				if field.GetType() == ElementTypeFile {
-					if val.(string) != "" {
-						_, fileName := filepath.Split(val.(string))
-						field.SetFile(&File{
-							Location: val.(string),
-							Name:     fileName,
-						})
-					}
-					continue
-				}
-				if strVal, ok := val.(string); ok {
-					field.SetValue(strVal)
-				}
-				if _, ok := val.(string); !ok {
-					field.SetValue(fmt.Sprintf("%s", val))
-				}
-			case "uint32":
-				field.SetValue(strconv.Itoa(int(val.(uint32))))
-			case "int64":
-				field.SetValue(strconv.Itoa(int(val.(int64))))
-			case "int32":
-				field.SetValue(strconv.Itoa(int(val.(int32))))
-			case "int16":
-				field.SetValue(strconv.Itoa(int(val.(int16))))
-			case "int8":
-				field.SetValue(strconv.Itoa(int(val.(int8))))
-			case "int":
-				field.SetValue(strconv.Itoa(val.(int)))
-			case "float64":
-				field.SetValue(strconv.FormatFloat(val.(float64), 'f', -1, 64))
-			case "float32":
-				field.SetValue(strconv.FormatFloat(float64(val.(float32)), 'f', -1, 32))
-			case "bool":
-				field.SetValue(strconv.FormatBool(val.(bool)))
-			case "struct":
-				vT := reflect.ValueOf(val).Type().String()
-				if vT == "time.Time" {
-					if tVal, ok := vField.Interface().(time.Time); ok {
-						field.SetValue(tVal.Format("2006-01-02"))
-						continue
-					}
-					fmt.Println("Time field could not type casting", vField.Interface())
-					continue
-				}
-				field.SetValue(strconv.Itoa(int(vField.Field(0).Int())))
-			default:
-				fmt.Println("unknown interface field kind", vType)
+// filesFromSlice reads the files of a []File, []*File or a []string of file
+// locations.
+func filesFromSlice(slice reflect.Value) []*File {
+	var files []*File
+	for i := 0; i < slice.Len(); i++ {
+		switch item := slice.Index(i).Interface().(type) {
+		case File:
+			files = append(files, &item)
+		case *File:
+			if item != nil {
+				files = append(files, item)
+			}
+		case string:
+			if item != "" {
+				_, fileName := filepath.Split(item)
+				files = append(files, &File{Location: item, Name: fileName})
 			}
 		}
 	}
+	return files
+}
+
+// elementTime parses v with the layout of a date or time element, other
+// elements hold LayoutDate values.
+func elementTime(field ElementInterface, v string) (time.Time, error) {
+	if temporal, ok := field.(TemporalElementInterface); ok {
+		return ParseTime(temporal.GetLayout(), v, temporal.GetLocation())
+	}
+	return time.Parse(LayoutDate, v)
 }
 
 func underscore(s string) string {
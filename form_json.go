@@ -0,0 +1,173 @@
+package goform
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/vincent-petithory/dataurl"
+)
+
+// BindFromJSON binds a JSON object like a submitted form. Nested objects bind
+// sub forms, arrays of objects collections and arrays of values multi valued
+// elements. File elements take data URLs, e.g.
+// "data:application/pdf;name=cv.pdf;base64,JVBERi0...".
+func (form *Form) BindFromJSON(r io.Reader) error {
+	return form.bindJSON(r, "")
+}
+
+func (form *Form) bindJSON(r io.Reader, remoteAddr string) error {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	var object map[string]interface{}
+	if err := decoder.Decode(&object); err != nil {
+		return err
+	}
+	val := url.Values{}
+	flattenJSON(val, "", object)
+	form.bindValues(val)
+	form.bindDataURLs(val)
+	form.bindCaptchas(val, remoteAddr)
+	return nil
+}
+
+// isJSONRequest reports whether the body of req is JSON, e.g. application/json
+// or application/problem+json.
+func isJSONRequest(req *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// flattenJSON adds value to val with the names a browser would submit, e.g.
+// "address[city]" for {"address": {"city": ...}} and "items[0][sku]" for
+// {"items": [{"sku": ...}]}. An empty array is added without values, so the
+// element is cleared.
+func flattenJSON(val url.Values, name string, value interface{}) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			flattenJSON(val, prefixName(name, key), item)
+		}
+	case []interface{}:
+		if _, ok := val[name]; !ok {
+			val[name] = []string{}
+		}
+		for i, item := range value {
+			switch item.(type) {
+			case map[string]interface{}, []interface{}:
+				flattenJSON(val, name+"["+strconv.Itoa(i)+"]", item)
+			default:
+				flattenJSON(val, name, item)
+			}
+		}
+	case string:
+		val.Add(name, value)
+	case json.Number:
+		val.Add(name, value.String())
+	case bool:
+		val.Add(name, strconv.FormatBool(value))
+	}
+}
+
+// bindDataURLs binds the data URLs of file elements, values which are not
+// valid data URLs are left out.
+func (form *Form) bindDataURLs(val url.Values) {
+	for name, values := range val {
+		key, ok := form.elementKey(name)
+		if !ok {
+			continue
+		}
+		field, err := form.Get(key)
+		if err != nil || field.GetType() != ElementTypeFile && field.GetType() != ElementTypeMultiFile {
+			continue
+		}
+		var files []*File
+		for _, value := range values {
+			if file, err := dataURLFile(value); err == nil {
+				files = append(files, file)
+			}
+		}
+		if field.GetType() == ElementTypeMultiFile {
+			field.SetFiles(files)
+			continue
+		}
+		if len(files) > 0 {
+			field.SetFile(files[len(files)-1])
+		}
+	}
+
+	for _, field := range form.GetElements() {
+		switch field := field.(type) {
+		case *CollectionElement:
+			_, values := submittedRows(val, field.Name, field.rowLimit())
+			for i, row := range field.Rows {
+				row.bindDataURLs(rowValues(values[field.submittedIndex(i)], row))
+			}
+		case *SubFormElement:
+			field.Form.bindDataURLs(nestedValues(val, field.Name))
+		}
+	}
+}
+
+// dataURLFile decodes a data URL to a file named after its name or filename
+// parameter.
+func dataURLFile(s string) (*File, error) {
+	d, err := dataurl.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	name := d.MediaType.Params["name"]
+	if name == "" {
+		name = d.MediaType.Params["filename"]
+	}
+	if name == "" {
+		name = "file." + d.MediaType.Subtype
+	}
+	fileParts := strings.Split(name, ".")
+	return &File{
+		Headers:   map[string][]string{"Content-Type": {d.MediaType.ContentType()}},
+		Name:      name,
+		Extension: fileParts[len(fileParts)-1],
+		Size:      int64(len(d.Data)),
+		Binary:    dataFile{bytes.NewReader(d.Data)},
+	}, nil
+}
+
+// dataFile is the multipart.File of a decoded data URL.
+type dataFile struct {
+	*bytes.Reader
+}
+
+func (dataFile) Close() error {
+	return nil
+}
+
+// GetErrorsJSON returns the messages of Errors keyed by element name and the
+// messages of the form, e.g.
+//
+//	{"errors":{"email":["This field is required"]},"form_errors":["..."]}
+//
+// The errors of a sub form or a collection row are keyed by its name.
+func (form *Form) GetErrorsJSON() ([]byte, error) {
+	messages := map[string][]string{}
+	var formMessages []string
+	for _, err := range form.Errors() {
+		if err.Field == "" {
+			formMessages = append(formMessages, err.Message)
+			continue
+		}
+		messages[err.Field] = append(messages[err.Field], err.Message)
+	}
+	return json.Marshal(struct {
+		Errors     map[string][]string `json:"errors"`
+		FormErrors []string            `json:"form_errors,omitempty"`
+	}{messages, formMessages})
+}
@@ -0,0 +1,93 @@
+package goform
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBindFromJSONSubFormPrefix(t *testing.T) {
+	// the values are flattened from a map, bind often enough to see every order
+	for i := 0; i < 100; i++ {
+		contact := NewGoForm()
+		contact.Add(NewEmailElement("email", "Email", nil, nil, nil))
+		form := NewGoForm()
+		form.Add(NewEmailElement("email", "Email", nil, nil, nil))
+		form.Add(NewSubFormElement("contact", "Contact", nil, contact))
+
+		if err := form.BindFromJSON(strings.NewReader(`{"email":"a@x","contact":{"email":"b@x"}}`)); err != nil {
+			t.Fatal(err)
+		}
+		email, _ := form.Get("email")
+		contactEmail, _ := contact.Get("email")
+		if email.GetValue() != "a@x" || contactEmail.GetValue() != "b@x" {
+			t.Fatalf("email = %q, contact[email] = %q, want %q, %q", email.GetValue(), contactEmail.GetValue(), "a@x", "b@x")
+		}
+	}
+}
+
+func TestBindFromJSONSubFormFile(t *testing.T) {
+	contact := NewGoForm()
+	contact.Add(NewFileElement("cv", "CV", nil, nil, nil, ""))
+	form := NewGoForm()
+	form.Add(NewFileElement("cv", "CV", nil, nil, nil, ""))
+	form.Add(NewSubFormElement("contact", "Contact", nil, contact))
+
+	body := `{"contact":{"cv":"data:application/pdf;name=cv.pdf;base64,JVBERi0xLjQ="}}`
+	if err := form.BindFromJSON(strings.NewReader(body)); err != nil {
+		t.Fatal(err)
+	}
+	cv, _ := form.Get("cv")
+	if cv.GetFile() != nil {
+		t.Errorf("cv = %q, want no file", cv.GetFile().Name)
+	}
+	contactCV, _ := contact.Get("cv")
+	if contactCV.GetFile() == nil || contactCV.GetFile().Name != "cv.pdf" {
+		t.Errorf("contact[cv] = %v, want cv.pdf", contactCV.GetFile())
+	}
+}
+
+func TestBindFromJSONCollectionFiles(t *testing.T) {
+	form := NewGoForm()
+	form.Add(NewCollectionElement("items", "Items", nil, func() []ElementInterface {
+		return []ElementInterface{NewFileElement("doc", "Doc", nil, nil, nil, "")}
+	}, 0, 0))
+
+	body := `{"items":[{"doc":"data:text/plain;name=a.txt;base64,YQ=="},{"doc":"data:text/plain;name=b.txt;base64,Yg=="}]}`
+	if err := form.BindFromJSON(strings.NewReader(body)); err != nil {
+		t.Fatal(err)
+	}
+	items, _ := form.Get("items")
+	for i, want := range []string{"a.txt", "b.txt"} {
+		doc, _ := items.(*CollectionElement).Rows[i].Get("doc")
+		if doc.GetFile() == nil || doc.GetFile().Name != want {
+			t.Errorf("row %d doc = %v, want %s", i, doc.GetFile(), want)
+		}
+	}
+}
+
+func TestGetErrorsJSON(t *testing.T) {
+	address := NewGoForm()
+	address.Add(NewTextElement("city", "City", nil, []ValidatorInterface{&RequiredValidator{}}, nil))
+	form := NewGoForm()
+	form.Add(NewEmailElement("email", "Email", nil, []ValidatorInterface{&RequiredValidator{}}, nil))
+	form.Add(NewSubFormElement("address", "Address", nil, address))
+	form.Add(NewCollectionElement("items", "Items", nil, func() []ElementInterface {
+		return []ElementInterface{NewTextElement("sku", "SKU", nil, []ValidatorInterface{&RequiredValidator{}}, nil)}
+	}, 0, 0))
+	if err := form.BindFromJSON(strings.NewReader(`{"items":[{"sku":""}]}`)); err != nil {
+		t.Fatal(err)
+	}
+	form.IsValid()
+	address.AddFormError("Address is not deliverable", nil)
+	form.AddFormError("Try again later", nil)
+
+	got, err := form.GetErrorsJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"errors":{"address":["Address is not deliverable"],"address[city]":["This field is required"],` +
+		`"email":["This field is required"],"items[0][sku]":["This field is required"]},"form_errors":["Try again later"]}`
+	if string(got) != want {
+		t.Errorf("GetErrorsJSON() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,427 @@
+package goform
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrStructExpected = errors.New("Struct or pointer to struct expected")
+)
+
+// NewFormFromStruct builds a form with an element for every exported field of
+// the struct v. The goform tag of a field holds the element name followed by
+// options separated by ";", e.g.
+//
+//	Email string `goform:"email;label=E-posta;type=email;required;maxlen=120"`
+//
+// The options are label, type, placeholder, class, options (value options
+// as "value:Label|value:Label"), required, minlen, maxlen, min, max and
+// pattern. Without a type the element type follows the field type: bool is a
+// checkbox, numbers are number elements, time.Time is a date, []string is a
+// multicheckbox, goform.File is a file, []goform.File a multi file, a struct
+// a sub form and a slice of structs a collection. Types with a converter or
+// implementing encoding.TextUnmarshaler are text elements, protobuf timestamps
+// are dates, wrapper messages follow the type of their value and enums are
+// selects of their names. Fields tagged "-" are left out. Unknown options,
+// invalid patterns and types containing themselves are errors.
+func NewFormFromStruct(v interface{}) (*Form, error) {
+	typ := reflect.TypeOf(v)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, ErrStructExpected
+	}
+	form, err := newFormFromType(typ, map[reflect.Type]bool{})
+	if err != nil {
+		return nil, fmt.Errorf("goform: %v", err)
+	}
+	return form, nil
+}
+
+// newFormFromType builds the form of typ. building holds the struct types
+// whose elements are being added, a type containing itself has no form.
+func newFormFromType(typ reflect.Type, building map[reflect.Type]bool) (*Form, error) {
+	form := NewGoForm()
+	if err := addStructElements(form, typ, building); err != nil {
+		return nil, err
+	}
+	return form, nil
+}
+
+// addStructElements adds the elements of the fields of typ, the fields of
+// embedded structs without a goform tag are added like fields of typ.
+func addStructElements(form *Form, typ reflect.Type, building map[reflect.Type]bool) error {
+	if building[typ] {
+		return errors.New("recursive type " + typ.String())
+	}
+	building[typ] = true
+	defer delete(building, typ)
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if embedded := field.Type; field.Anonymous && field.Tag.Get("goform") == "" {
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct && embedded != timeType && embedded != fileType {
+				if err := addStructElements(form, embedded, building); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		tag, ok := parseStructTag(field)
+		if !ok {
+			continue
+		}
+		element, err := newStructFieldElement(field.Type, tag, building)
+		if err != nil {
+			return fmt.Errorf("field %s: %v", field.Name, err)
+		}
+		form.Add(element)
+	}
+	return nil
+}
+
+type structTag struct {
+	name    string
+	options map[string]string
+}
+
+func (tag structTag) has(option string) bool {
+	_, ok := tag.options[option]
+	return ok
+}
+
+// parseStructTag reads the goform tag of a field, options without a value
+// such as required are stored with an empty value.
+func parseStructTag(field reflect.StructField) (structTag, bool) {
+	tag := structTag{options: map[string]string{}}
+	name, ok := elementName(field)
+	if !ok {
+		return tag, false
+	}
+	tag.name = name
+	parts := strings.Split(field.Tag.Get("goform"), ";")
+	for _, part := range parts[1:] {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) == 2 {
+			tag.options[kv[0]] = kv[1]
+		} else {
+			tag.options[kv[0]] = ""
+		}
+	}
+	if _, ok := tag.options["label"]; !ok {
+		label := strings.Replace(underscore(field.Name), "_", " ", -1)
+		first, size := utf8.DecodeRuneInString(label)
+		tag.options["label"] = string(unicode.ToUpper(first)) + label[size:]
+	}
+	return tag, true
+}
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	fileType        = reflect.TypeOf(File{})
+	filePtrType     = reflect.TypeOf(&File{})
+	temporalLayouts = map[string]string{
+		"date":           LayoutDate,
+		"time":           LayoutTime,
+		"datetime-local": LayoutDateTimeLocal,
+		"month":          LayoutMonth,
+		"week":           LayoutWeek,
+	}
+)
+
+// inferElementType returns the element type for a field type, "" if the type
+// is not supported.
+func inferElementType(typ reflect.Type, tag structTag) string {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	switch {
+	case typ == timeType || isTimestamp(typ):
+		return "date"
+	case typ == fileType:
+		return "file"
+	case isWrapperMessage(typ):
+		index, _ := wrapperField(typ)
+		return inferElementType(typ.Field(index).Type, tag)
+	case converterChain(nil).canParse(typ):
+		return "text"
+	}
+	if _, ok := enumNames(typ); ok {
+		return "select"
+	}
+	switch typ.Kind() {
+	case reflect.Bool:
+		return "checkbox"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if tag.has("options") {
+			return "select"
+		}
+		return "number"
+	case reflect.String:
+		if tag.has("options") {
+			return "select"
+		}
+		return "text"
+	case reflect.Struct:
+		return "subform"
+	case reflect.Slice:
+		elem := typ.Elem()
+		switch {
+		case elem == fileType || elem == filePtrType:
+			return "multifile"
+		case elem.Kind() == reflect.Struct || elem.Kind() == reflect.Ptr && elem.Elem().Kind() == reflect.Struct:
+			return "collection"
+		case elem.Kind() == reflect.String || elem.Kind() >= reflect.Int && elem.Kind() <= reflect.Float64,
+			converterChain(nil).canParse(elem):
+			return "multicheckbox"
+		}
+	}
+	return ""
+}
+
+// structTagOptions are the options a goform tag may have.
+var structTagOptions = map[string]bool{
+	"label": true, "type": true, "placeholder": true, "class": true, "options": true,
+	"required": true, "minlen": true, "maxlen": true, "min": true, "max": true, "pattern": true,
+}
+
+func newStructFieldElement(typ reflect.Type, tag structTag, building map[reflect.Type]bool) (ElementInterface, error) {
+	var unknown []string
+	for key := range tag.options {
+		if !structTagOptions[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, errors.New("unknown option " + strings.Join(unknown, ", "))
+	}
+
+	elementType := tag.options["type"]
+	if elementType == "" {
+		elementType = inferElementType(typ, tag)
+	}
+	if elementType == "" {
+		return nil, errors.New("unsupported type " + typ.String())
+	}
+
+	name, label := tag.name, tag.options["label"]
+	var attributes []*Attribute
+	for _, key := range []string{"placeholder", "class"} {
+		if value, ok := tag.options[key]; ok {
+			attributes = append(attributes, &Attribute{Key: key, Value: value})
+		}
+	}
+	valueOptions := structTagValueOptions(tag.options["options"])
+	if len(valueOptions) == 0 {
+		valueOptions = structEnumValueOptions(typ)
+	}
+
+	switch elementType {
+	case "subform", "collection":
+		structType := typ
+		for structType.Kind() == reflect.Ptr || structType.Kind() == reflect.Slice {
+			structType = structType.Elem()
+		}
+		if structType.Kind() != reflect.Struct {
+			return nil, errors.New(elementType + " needs a struct, not " + typ.String())
+		}
+		sub, err := newFormFromType(structType, building)
+		if err != nil {
+			return nil, err
+		}
+		if elementType == "subform" {
+			return NewSubFormElement(name, label, attributes, sub), nil
+		}
+		min, max, err := structTagRange(tag)
+		if err != nil {
+			return nil, err
+		}
+		return NewCollectionElement(name, label, attributes, func() []ElementInterface {
+			row, _ := newFormFromType(structType, map[reflect.Type]bool{})
+			return row.GetElements()
+		}, int(min), int(max)), nil
+	}
+
+	validators, err := structTagValidators(tag, elementType)
+	if err != nil {
+		return nil, err
+	}
+
+	switch elementType {
+	case "text":
+		return NewTextElement(name, label, attributes, validators, nil), nil
+	case "email":
+		validators = append(validators, &EmailAddressValidator{})
+		return NewEmailElement(name, label, attributes, validators, nil), nil
+	case "password":
+		return NewPasswordElement(name, label, attributes, validators, nil), nil
+	case "search":
+		return NewSearchElement(name, label, attributes, validators, nil), nil
+	case "tel":
+		return NewTelElement(name, label, attributes, validators, nil), nil
+	case "number":
+		return NewNumberElement(name, label, attributes, validators, nil), nil
+	case "textarea":
+		return NewTextareaElement(name, label, attributes, validators, nil), nil
+	case "hidden":
+		return NewHiddenElement(name, attributes, validators, nil), nil
+	case "checkbox":
+		return NewCheckboxElement(name, label, attributes, validators, nil), nil
+	case "select":
+		return NewSelectElement(name, label, attributes, valueOptions, validators, nil), nil
+	case "radio":
+		return NewRadioElement(name, label, attributes, valueOptions, validators, nil), nil
+	case "multicheckbox":
+		return NewMultiCheckboxElement(name, label, attributes, valueOptions, validators, nil), nil
+	case "multiselect":
+		return NewMultiSelectElement(name, label, attributes, valueOptions, validators, nil), nil
+	case "file":
+		return NewFileElement(name, label, attributes, validators, nil, ""), nil
+	case "multifile":
+		return NewMultiFileElement(name, label, attributes, validators, nil), nil
+	case "date":
+		return NewDateElement(name, label, attributes, validators, nil), nil
+	case "time":
+		return NewTimeElement(name, label, attributes, validators, nil), nil
+	case "datetime-local":
+		return NewDateTimeLocalElement(name, label, attributes, validators, nil), nil
+	case "month":
+		return NewMonthElement(name, label, attributes, validators, nil), nil
+	case "week":
+		return NewWeekElement(name, label, attributes, validators, nil), nil
+	}
+	return nil, errors.New("unknown element type " + elementType)
+}
+
+// structEnumValueOptions returns the values of an enum, a pointer or a slice of
+// enums as options.
+func structEnumValueOptions(typ reflect.Type) []*ValueOption {
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+	}
+	names, ok := enumNames(typ)
+	if !ok {
+		return nil
+	}
+	return enumValueOptions(names)
+}
+
+// structTagValueOptions parses "value:Label|value:Label", a value without a
+// label is its own label.
+func structTagValueOptions(s string) []*ValueOption {
+	var valueOptions []*ValueOption
+	if s == "" {
+		return valueOptions
+	}
+	for _, option := range strings.Split(s, "|") {
+		kv := strings.SplitN(option, ":", 2)
+		valueOption := &ValueOption{Value: kv[0], Label: kv[0]}
+		if len(kv) == 2 {
+			valueOption.Label = kv[1]
+		}
+		valueOptions = append(valueOptions, valueOption)
+	}
+	return valueOptions
+}
+
+func structTagRange(tag structTag) (min float64, max float64, err error) {
+	if value, ok := tag.options["min"]; ok {
+		if min, err = strconv.ParseFloat(value, 64); err != nil {
+			return 0, 0, errors.New("invalid min " + value)
+		}
+	}
+	if value, ok := tag.options["max"]; ok {
+		if max, err = strconv.ParseFloat(value, 64); err != nil {
+			return 0, 0, errors.New("invalid max " + value)
+		}
+	}
+	return min, max, nil
+}
+
+// structTagValidators builds the validators of the tag options. min and max
+// compare values of number elements, dates of date and time elements, parsed
+// with the layout of the element, and count the files of multi file elements.
+func structTagValidators(tag structTag, elementType string) ([]ValidatorInterface, error) {
+	var validators []ValidatorInterface
+	if tag.has("required") {
+		validators = append(validators, &RequiredValidator{})
+	}
+	for _, key := range []string{"minlen", "maxlen"} {
+		value, ok := tag.options[key]
+		if !ok {
+			continue
+		}
+		length, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, errors.New("invalid " + key + " " + value)
+		}
+		if key == "minlen" {
+			validators = append(validators, &MinLengthValidator{Length: length})
+		} else {
+			validators = append(validators, &MaxLengthValidator{Length: length})
+		}
+	}
+	if pattern, ok := tag.options["pattern"]; ok {
+		validator := &RegexValidator{Pattern: pattern}
+		if _, err := validator.compile(); err != nil {
+			return nil, errors.New("invalid pattern " + pattern)
+		}
+		validators = append(validators, validator)
+	}
+
+	for _, key := range []string{"min", "max"} {
+		value, ok := tag.options[key]
+		if !ok {
+			continue
+		}
+		if layout, ok := temporalLayouts[elementType]; ok {
+			t, err := ParseTime(layout, value, nil)
+			if err != nil {
+				return nil, errors.New("invalid " + key + " " + value)
+			}
+			if key == "min" {
+				validators = append(validators, &MinDateValidator{Min: t})
+			} else {
+				validators = append(validators, &MaxDateValidator{Max: t})
+			}
+			continue
+		}
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, errors.New("invalid " + key + " " + value)
+		}
+		switch {
+		case elementType == "multifile" && key == "min":
+			validators = append(validators, &MinFilesValidator{Min: int(n)})
+		case elementType == "multifile":
+			validators = append(validators, &MaxFilesValidator{Max: int(n)})
+		case key == "min":
+			validators = append(validators, &MinValueValidator{Min: n})
+		default:
+			validators = append(validators, &MaxValueValidator{Max: n})
+		}
+	}
+	return validators, nil
+}
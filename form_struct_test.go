@@ -0,0 +1,247 @@
+package goform
+
+import (
+	"html"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseStructTagLabel(t *testing.T) {
+	type model struct {
+		FirstName string
+		Şehir     string
+		ŞehirAdı  string
+		Über      string
+		Email     string `goform:"email;label=E-posta"`
+	}
+	want := map[string]string{
+		"FirstName": "First name",
+		"Şehir":     "Şehir",
+		"ŞehirAdı":  "Şehir adı",
+		"Über":      "Über",
+		"Email":     "E-posta",
+	}
+	typ := reflect.TypeOf(model{})
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag, ok := parseStructTag(field)
+		if !ok {
+			t.Fatalf("parseStructTag(%s) not ok", field.Name)
+		}
+		if label := tag.options["label"]; label != want[field.Name] {
+			t.Errorf("label of %s = %q, want %q", field.Name, label, want[field.Name])
+		}
+	}
+}
+
+var (
+	htmlTemplates  = regexp.MustCompile(`(?s)<template.*?</template>`)
+	htmlInputs     = regexp.MustCompile(`<input\s[^>]*>`)
+	htmlSelects    = regexp.MustCompile(`(?s)<select(\s[^>]*)>(.*?)</select>`)
+	htmlOptions    = regexp.MustCompile(`<option\s[^>]*>`)
+	htmlTextareas  = regexp.MustCompile(`(?s)<textarea(\s[^>]*)>(.*?)</textarea>`)
+	htmlAttributes = regexp.MustCompile(`\s([a-z-]+)(?:="([^"]*)")?`)
+)
+
+func htmlAttributeMap(tag string) map[string]string {
+	attributes := map[string]string{}
+	for _, match := range htmlAttributes.FindAllStringSubmatch(tag, -1) {
+		attributes[match[1]] = html.UnescapeString(match[2])
+	}
+	return attributes
+}
+
+// postedValues returns the values a browser posts for the rendered form, the
+// prototypes of collections are not part of it.
+func postedValues(out string) url.Values {
+	out = htmlTemplates.ReplaceAllString(out, "")
+	values := url.Values{}
+	for _, input := range htmlInputs.FindAllString(out, -1) {
+		attributes := htmlAttributeMap(input)
+		switch attributes["type"] {
+		case "submit", "button", "image", "file":
+			continue
+		case "checkbox", "radio":
+			if _, ok := attributes["checked"]; !ok {
+				continue
+			}
+		}
+		values.Add(attributes["name"], attributes["value"])
+	}
+	for _, match := range htmlSelects.FindAllStringSubmatch(out, -1) {
+		name := htmlAttributeMap(match[1])["name"]
+		for _, option := range htmlOptions.FindAllString(match[2], -1) {
+			attributes := htmlAttributeMap(option)
+			if _, ok := attributes["selected"]; ok {
+				values.Add(name, attributes["value"])
+			}
+		}
+	}
+	for _, match := range htmlTextareas.FindAllStringSubmatch(out, -1) {
+		// browsers drop the newline right after the start tag
+		text := strings.TrimPrefix(html.UnescapeString(match[2]), "\n")
+		values.Add(htmlAttributeMap(match[1])["name"], text)
+	}
+	return values
+}
+
+type roundTripAddress struct {
+	City string
+	Zip  int `goform:"postal_code"`
+}
+
+type roundTripLine struct {
+	SKU string `goform:"sku;required"`
+	Qty int
+}
+
+type roundTripModel struct {
+	Name     string    `goform:"full_name;label=Name;required"`
+	Email    string    `goform:"email;type=email"`
+	Bio      string    `goform:"bio;type=textarea"`
+	Age      int       `goform:"age"`
+	Score    float64   `goform:"score"`
+	Active   bool      `goform:"active"`
+	Color    string    `goform:"color;options=red:Red|blue:Blue"`
+	Size     string    `goform:"size;type=radio;options=s:S|m:M"`
+	Tags     []string  `goform:"tags;options=a:A|b:B|c:C"`
+	Born     time.Time `goform:"born"`
+	Nickname *string
+	Address  roundTripAddress
+	Billing  *roundTripAddress `goform:"billing_address"`
+	Lines    []roundTripLine   `goform:"lines"`
+	Secret   string            `goform:"-"`
+}
+
+func TestStructRoundTrip(t *testing.T) {
+	nickname := "Ada"
+	tests := []struct {
+		name  string
+		model roundTripModel
+	}{
+		{"empty", roundTripModel{}},
+		{"filled", roundTripModel{
+			Name:     "Semih",
+			Email:    "semih@example.com",
+			Bio:      "Writes forms.",
+			Age:      36,
+			Score:    9.5,
+			Active:   true,
+			Color:    "blue",
+			Size:     "m",
+			Tags:     []string{"a", "c"},
+			Born:     time.Date(1988, 4, 23, 0, 0, 0, 0, time.UTC),
+			Nickname: &nickname,
+			Address:  roundTripAddress{City: "İzmir", Zip: 35000},
+			Billing:  &roundTripAddress{City: "Ankara", Zip: 6000},
+			Lines:    []roundTripLine{{SKU: "A-1", Qty: 2}, {SKU: "B-2", Qty: 1}},
+		}},
+		{"markup", roundTripModel{
+			Name:    `"Semih" <&> 'S'`,
+			Bio:     "\nfirst line\n<b>second</b> & third",
+			Address: roundTripAddress{City: `</textarea><script>`},
+			Lines:   []roundTripLine{{SKU: `"quoted"`}},
+		}},
+	}
+	for themeName, theme := range testThemes {
+		for _, test := range tests {
+			t.Run(themeName+"/"+test.name, func(t *testing.T) {
+				form, err := NewFormFromStruct(&roundTripModel{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				form.SetTheme(theme)
+				form.BindFromInterface(&test.model)
+				values := postedValues(form.Render())
+
+				req := httptest.NewRequest("POST", "/", strings.NewReader(values.Encode()))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				if err := req.ParseForm(); err != nil {
+					t.Fatal(err)
+				}
+				posted, _ := NewFormFromStruct(&roundTripModel{})
+				posted.BindFromRequest(req)
+				model, err := Bind[roundTripModel](posted)
+				if err != nil {
+					t.Fatalf("Bind() error = %v, posted %v", err, values)
+				}
+				if !reflect.DeepEqual(model, test.model) {
+					t.Errorf("posted %v\nmapped %+v\nwant   %+v", values, model, test.model)
+				}
+			})
+		}
+	}
+}
+
+type recursiveNode struct {
+	Name     string
+	Children []recursiveNode
+}
+
+type recursiveParent struct {
+	Name   string
+	Parent *recursiveParent
+}
+
+type recursiveA struct{ B recursiveB }
+
+type recursiveB struct{ A *recursiveA }
+
+type recursiveEmbedded struct {
+	*recursiveEmbedded
+	Name string
+}
+
+func TestNewFormFromStructErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		model interface{}
+		err   string
+	}{
+		{"collection of itself", recursiveNode{}, "goform: field Children: recursive type goform.recursiveNode"},
+		{"pointer to itself", &recursiveParent{}, "goform: field Parent: recursive type goform.recursiveParent"},
+		{"through another struct", recursiveA{}, "goform: field B: field A: recursive type goform.recursiveA"},
+		{"embedded itself", recursiveEmbedded{}, "goform: recursive type goform.recursiveEmbedded"},
+		{"unknown option", struct {
+			Name string `goform:"name;maxlength=120;required"`
+		}{}, "goform: field Name: unknown option maxlength"},
+		{"unknown options", struct {
+			Name string `goform:"name;requird;lable=Name"`
+		}{}, "goform: field Name: unknown option lable, requird"},
+		{"invalid pattern", struct {
+			Code string `goform:"code;pattern=[A-Z"`
+		}{}, "goform: field Code: invalid pattern [A-Z"},
+		{"invalid maxlen", struct {
+			Name string `goform:"name;maxlen=many"`
+		}{}, "goform: field Name: invalid maxlen many"},
+		{"not a struct", "name", ErrStructExpected.Error()},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewFormFromStruct(test.model)
+			if err == nil || err.Error() != test.err {
+				t.Errorf("NewFormFromStruct() error = %v, want %q", err, test.err)
+			}
+		})
+	}
+}
+
+func TestNewFormFromStructPattern(t *testing.T) {
+	form, err := NewFormFromStruct(struct {
+		Code string `goform:"code;pattern=[A-Z]{3}"`
+	}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for value, valid := range map[string]bool{"ABC": true, "ABCD": false, "abc": false, "": true} {
+		form.bindValues(url.Values{"code": {value}})
+		if got := form.IsValid(); got != valid {
+			t.Errorf("IsValid() with code %q = %v, want %v", value, got, valid)
+		}
+	}
+}
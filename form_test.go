@@ -0,0 +1,301 @@
+package goform
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func newAddressForm() *Form {
+	address := NewGoForm()
+	address.Add(NewTextElement("city", "City", nil, nil, nil))
+
+	form := NewGoForm()
+	form.Add(NewTextElement("city", "City", nil, nil, nil))
+	form.Add(NewSubFormElement("address", "Address", nil, address))
+	return form
+}
+
+func TestBindValuesSubFormPrefix(t *testing.T) {
+	tests := []struct {
+		name        string
+		values      url.Values
+		city        string
+		addressCity string
+	}{
+		{"top level only", url.Values{"city": {"TOP"}}, "TOP", ""},
+		{"sub form only", url.Values{"address[city]": {"SUB"}}, "", "SUB"},
+		{"both", url.Values{"city": {"TOP"}, "address[city]": {"SUB"}}, "TOP", "SUB"},
+		{"other prefix", url.Values{"addressx[city]": {"X"}}, "", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			form := newAddressForm()
+			form.bindValues(test.values)
+
+			city, _ := form.Get("city")
+			if city.GetValue() != test.city {
+				t.Errorf("city = %q, want %q", city.GetValue(), test.city)
+			}
+			address, _ := form.Get("address")
+			addressCity, _ := address.(*SubFormElement).Form.Get("city")
+			if addressCity.GetName() != "address[city]" {
+				t.Errorf("name = %q, want %q", addressCity.GetName(), "address[city]")
+			}
+			if addressCity.GetValue() != test.addressCity {
+				t.Errorf("address[city] = %q, want %q", addressCity.GetValue(), test.addressCity)
+			}
+		})
+	}
+}
+
+func TestElementKey(t *testing.T) {
+	form := NewGoForm()
+	form.setNamePrefix("items[0]")
+	tests := []struct {
+		name string
+		key  string
+		ok   bool
+	}{
+		{"items[0][sku]", "sku", true},
+		{"items[0][tags][]", "tags", true},
+		{"sku", "sku", false},
+		{"items[1][sku]", "items[1][sku]", false},
+	}
+	for _, test := range tests {
+		key, ok := form.elementKey(test.name)
+		if key != test.key || ok != test.ok {
+			t.Errorf("elementKey(%q) = %q, %v, want %q, %v", test.name, key, ok, test.key, test.ok)
+		}
+	}
+}
+
+func TestValidateResetsErrors(t *testing.T) {
+	form := NewGoForm()
+	form.Add(NewTextElement("name", "Name", nil, []ValidatorInterface{&RequiredValidator{}}, nil))
+	form.Add(NewDateElement("start", "Start", nil, nil, nil))
+	form.Add(NewDateElement("end", "End", nil, nil, nil))
+	form.AddValidator(&DateRangeValidator{Start: "start", End: "end"})
+	form.bindValues(url.Values{"start": {"2020-02-01"}, "end": {"2020-01-01"}})
+
+	for i := 0; i < 2; i++ {
+		if form.IsValid() {
+			t.Fatal("IsValid() = true, want false")
+		}
+	}
+	name, _ := form.Get("name")
+	end, _ := form.Get("end")
+	if len(name.GetErrors()) != 1 || len(end.GetErrors()) != 1 {
+		t.Errorf("errors = %v, %v, want one each", name.GetErrors(), end.GetErrors())
+	}
+
+	form.bindValues(url.Values{"name": {"Semih"}, "start": {"2020-01-01"}, "end": {"2020-02-01"}})
+	if !form.IsValid() {
+		t.Fatalf("IsValid() = false, want true: %v", form.GetErrorMessages())
+	}
+	if form.HasError() || len(name.GetErrors()) != 0 || len(end.GetErrors()) != 0 {
+		t.Errorf("errors = %v, want none", form.GetErrorMessages())
+	}
+}
+
+func TestSumValidatorNotANumber(t *testing.T) {
+	form := NewGoForm()
+	form.Add(NewTextElement("a", "A", nil, nil, nil))
+	form.Add(NewTextElement("b", "B", nil, nil, nil))
+	form.AddValidator(&SumValidator{Elements: []string{"a", "b"}, Sum: 100})
+	form.bindValues(url.Values{"a": {"40"}, "b": {"sixty"}})
+
+	if form.IsValid() {
+		t.Fatal("IsValid() = true, want false")
+	}
+	b, _ := form.Get("b")
+	if messages := b.GetErrorMessages(); len(messages) != 1 || messages[0] != "Value must be a number" {
+		t.Errorf("errors = %v, want %q", messages, "Value must be a number")
+	}
+}
+
+func TestBindFromRequestCollectionFiles(t *testing.T) {
+	form := NewGoForm()
+	form.Add(NewFileElement("doc", "Doc", nil, nil, nil, ""))
+	form.Add(NewCollectionElement("items", "Items", nil, func() []ElementInterface {
+		return []ElementInterface{
+			NewTextElement("sku", "SKU", nil, nil, nil),
+			NewFileElement("doc", "Doc", nil, nil, nil, ""),
+		}
+	}, 0, 0))
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("items[3][sku]", "a")
+	writer.WriteField("items[7][sku]", "b")
+	for name, file := range map[string]string{"items[3][doc]": "a.pdf", "items[9][doc]": "c.pdf"} {
+		part, _ := writer.CreateFormFile(name, file)
+		part.Write([]byte("%PDF"))
+	}
+	writer.Close()
+	req := httptest.NewRequest("POST", "/", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if err := req.ParseMultipartForm(1 << 20); err != nil {
+		t.Fatal(err)
+	}
+	form.BindFromRequest(req)
+
+	if doc, _ := form.Get("doc"); doc.GetFile() != nil {
+		t.Errorf("doc = %q, want no file", doc.GetFile().Name)
+	}
+	items, _ := form.Get("items")
+	rows := items.(*CollectionElement).Rows
+	want := []struct{ sku, doc string }{{"a", "a.pdf"}, {"b", ""}, {"", "c.pdf"}}
+	if len(rows) != len(want) {
+		t.Fatalf("bound %d rows, want %d", len(rows), len(want))
+	}
+	for i, row := range rows {
+		sku, _ := row.Get("sku")
+		doc, _ := row.Get("doc")
+		var name string
+		if doc.GetFile() != nil {
+			name = doc.GetFile().Name
+		}
+		if sku.GetValue() != want[i].sku || name != want[i].doc {
+			t.Errorf("row %d = %q, %q, want %q, %q", i, sku.GetValue(), name, want[i].sku, want[i].doc)
+		}
+		if doc.GetName() != "items["+strconv.Itoa(i)+"][doc]" {
+			t.Errorf("row %d is named %q", i, doc.GetName())
+		}
+	}
+}
+
+func TestBindValuesCollectionMax(t *testing.T) {
+	form := NewGoForm()
+	form.Add(NewCollectionElement("items", "Items", nil, func() []ElementInterface {
+		return []ElementInterface{NewTextElement("sku", "SKU", nil, nil, nil)}
+	}, 0, 5))
+	val := url.Values{}
+	for i := 0; i < 50000; i++ {
+		val.Set("items["+strconv.Itoa(i)+"][sku]", strconv.Itoa(i))
+	}
+	form.bindValues(val)
+
+	items, _ := form.Get("items")
+	rows := items.(*CollectionElement).Rows
+	if len(rows) != 6 {
+		t.Fatalf("bound %d rows, want 6", len(rows))
+	}
+	for i, row := range rows {
+		if sku, _ := row.Get("sku"); sku.GetValue() != strconv.Itoa(i) {
+			t.Errorf("row %d sku = %q, want %q", i, sku.GetValue(), strconv.Itoa(i))
+		}
+	}
+	if form.IsValid() {
+		t.Fatal("IsValid() = true, want false")
+	}
+	if messages := items.GetErrorMessages(); len(messages) != 1 || messages[0] != "At most 5 rows are allowed" {
+		t.Errorf("errors = %v", messages)
+	}
+}
+
+func TestBindFilesCollectionMax(t *testing.T) {
+	items := NewCollectionElement("items", "Items", nil, func() []ElementInterface {
+		return []ElementInterface{NewFileElement("doc", "Doc", nil, nil, nil, "")}
+	}, 0, 1)
+	items.bindValues(url.Values{"items[5][doc]": {""}})
+	headers := map[string][]*multipart.FileHeader{}
+	for _, index := range []string{"1", "3", "9"} {
+		headers["items["+index+"][doc]"] = []*multipart.FileHeader{{Filename: index + ".pdf"}}
+	}
+	items.bindFiles(headers)
+
+	if len(items.Rows) != 2 {
+		t.Fatalf("bound %d rows, want 2", len(items.Rows))
+	}
+	for i, want := range []int{1, 3} {
+		if index := items.submittedIndex(i); index != want {
+			t.Errorf("row %d was submitted as %d, want %d", i, index, want)
+		}
+	}
+}
+
+func TestBindReplacesSelection(t *testing.T) {
+	selected := func(options []*ValueOption) string {
+		var values []string
+		for _, option := range options {
+			if option.Selected {
+				values = append(values, option.Value)
+			}
+		}
+		return strings.Join(values, ",")
+	}
+	tests := []struct {
+		name    string
+		element func(options []*ValueOption) ElementInterface
+		values  []url.Values
+		want    string
+	}{
+		{"select", selectElement, []url.Values{{"x": {"c"}}}, "c"},
+		{"select rebound", selectElement, []url.Values{{"x": {"c"}}, {"x": {"a"}}}, "a"},
+		{"unknown value", selectElement, []url.Values{{"x": {"z"}}}, ""},
+		{"radio", radioElement, []url.Values{{"x": {"b"}}}, "b"},
+		{"multiselect", multiSelectElement, []url.Values{{"x[]": {"b", "c"}}}, "b,c"},
+		{"multiselect rebound", multiSelectElement, []url.Values{{"x[]": {"b", "c"}}, {"x": {"a"}}}, "a"},
+		{"multiselect cleared", multiSelectElement, []url.Values{{}}, ""},
+		{"multicheckbox", multiCheckboxElement, []url.Values{{"x[]": {"c"}}}, "c"},
+		{"multicheckbox cleared", multiCheckboxElement, []url.Values{{"x[]": {"c"}}, {}}, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := selectionOptions()
+			form := NewGoForm()
+			form.Add(test.element(options))
+			for _, values := range test.values {
+				form.bindValues(values)
+			}
+			if got := selected(options); got != test.want {
+				t.Errorf("selected %q, want %q", got, test.want)
+			}
+		})
+	}
+
+	type model struct {
+		Tags []string
+	}
+	options := selectionOptions()
+	tags := NewMultiSelectElement("tags", "Tags", nil, options, nil, nil)
+	form := NewGoForm()
+	form.Add(tags)
+	form.BindFromInterface(&model{Tags: []string{"c"}})
+	if got := selected(options); got != "c" {
+		t.Errorf("selected %q after BindFromInterface, want %q", got, "c")
+	}
+	out := tags.Render()
+	if !strings.Contains(out, `<option value="c" selected>`) || strings.Contains(out, `<option value="a" selected>`) {
+		t.Errorf("rendered the wrong selection:\n%s", out)
+	}
+	if !strings.Contains(out, " multiple") {
+		t.Errorf("rendered no multiple attribute:\n%s", out)
+	}
+}
+
+// selectionOptions returns options of which a and b are selected by default.
+func selectionOptions() []*ValueOption {
+	return []*ValueOption{{Value: "a", Label: "A", Selected: true}, {Value: "b", Label: "B", Selected: true}, {Value: "c", Label: "C"}}
+}
+
+func selectElement(options []*ValueOption) ElementInterface {
+	return NewSelectElement("x", "X", nil, options, nil, nil)
+}
+
+func radioElement(options []*ValueOption) ElementInterface {
+	return NewRadioElement("x", "X", nil, options, nil, nil)
+}
+
+func multiSelectElement(options []*ValueOption) ElementInterface {
+	return NewMultiSelectElement("x", "X", nil, options, nil, nil)
+}
+
+func multiCheckboxElement(options []*ValueOption) ElementInterface {
+	return NewMultiCheckboxElement("x", "X", nil, options, nil, nil)
+}
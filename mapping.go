@@ -0,0 +1,238 @@
+package goform
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrPointerExpected  = errors.New("Pointer to struct expected")
+	ErrUnsupportedField = errors.New("Unsupported field type")
+)
+
+// FieldError is a value which could not be mapped to a field. Field is the
+// path of the struct field, e.g. "Address.Zip" or "Items[1].Quantity", Element
+// the name of the element and Value the value submitted.
+type FieldError struct {
+	Field   string
+	Element string
+	Value   string
+	Err     error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("goform: field %s (%s): cannot map %q: %v", e.Field, e.Element, e.Value, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// FieldErrors holds every field MapTo could not map.
+type FieldErrors []*FieldError
+
+func (errs FieldErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, e := range errs {
+		messages[i] = e.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (errs FieldErrors) Unwrap() []error {
+	unwrapped := make([]error, len(errs))
+	for i, e := range errs {
+		unwrapped[i] = e
+	}
+	return unwrapped
+}
+
+// Bind maps the form to a new T, a struct or a pointer to struct, see MapTo.
+func Bind[T any](form *Form) (T, error) {
+	var model T
+	value := reflect.ValueOf(&model).Elem()
+	if value.Kind() == reflect.Ptr {
+		value.Set(reflect.New(value.Type().Elem()))
+		return model, form.MapTo(model)
+	}
+	return model, form.MapTo(&model)
+}
+
+// mapField maps an element to a field, a failed conversion is added to the
+// errors of the element.
+func mapField(field ElementInterface, workField reflect.Value, path string, chain converterChain) FieldErrors {
+	switch element := field.(type) {
+	case *SubFormElement:
+		return element.mapTo(workField, path, chain)
+	case *CollectionElement:
+		return element.mapTo(workField, path, chain)
+	}
+
+	v := field.GetValue()
+	if field.IsMultiple() {
+		v = strings.Join(field.GetValues(), " ")
+	}
+
+	typ := workField.Type()
+	isPtr := typ.Kind() == reflect.Ptr
+	if isPtr {
+		// an empty value is a nil pointer
+		if v == "" && field.GetFile() == nil && len(field.GetFiles()) == 0 {
+			workField.Set(reflect.Zero(workField.Type()))
+			return nil
+		}
+		typ = typ.Elem()
+	}
+
+	value, err := mapValue(field, v, typ, chain)
+	if err != nil {
+		if message := conversionMessage(typ, err); message != "" && !hasMessage(field, message) {
+			field.AddError(message, nil)
+		}
+		return FieldErrors{{Field: path, Element: field.GetName(), Value: v, Err: err}}
+	}
+	if isPtr {
+		ptr := reflect.New(typ)
+		ptr.Elem().Set(value)
+		value = ptr
+	}
+	workField.Set(value)
+	return nil
+}
+
+// hasMessage reports whether the element has the error already, e.g. from
+// mapping the form before.
+func hasMessage(field ElementInterface, message string) bool {
+	for _, m := range field.GetErrors() {
+		if m.Message == message {
+			return true
+		}
+	}
+	return false
+}
+
+// mapValue converts the value v of an element to typ. An empty value is the
+// zero value of typ, a nil slice for slices.
+func mapValue(field ElementInterface, v string, typ reflect.Type, chain converterChain) (reflect.Value, error) {
+	if typ == fileType {
+		if field.GetFile() == nil {
+			return reflect.Zero(typ), nil
+		}
+		return reflect.ValueOf(*field.GetFile()), nil
+	}
+	if chain.canParse(typ) {
+		return chain.parse(field, v, typ)
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		// a checkbox is checked by any value, e.g. the "on" browsers submit
+		if field.GetType() == ElementTypeCheckbox {
+			return reflect.ValueOf(field.IsChecked()).Convert(typ), nil
+		}
+	case reflect.Slice:
+		if field.GetType() == ElementTypeMultiFile {
+			return fileSliceValue(field.GetFiles(), typ)
+		}
+		values := strings.Fields(v)
+		if field.IsMultiple() {
+			values = field.GetValues()
+		}
+		if len(values) == 0 {
+			return reflect.Zero(typ), nil
+		}
+		slice := reflect.MakeSlice(typ, 0, len(values))
+		for _, item := range values {
+			value, err := chain.parse(field, item, typ.Elem())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			slice = reflect.Append(slice, value)
+		}
+		return slice, nil
+	}
+	return chain.parse(field, v, typ)
+}
+
+// parseValue converts v to the string, bool or number typ.
+func parseValue(v string, typ reflect.Type) (reflect.Value, error) {
+	value := reflect.New(typ).Elem()
+	switch typ.Kind() {
+	case reflect.String:
+		value.SetString(v)
+		return value, nil
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if v == "" {
+			return value, nil
+		}
+	default:
+		return value, ErrUnsupportedField
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return value, err
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(v, 10, typ.Bits())
+		if err != nil {
+			return value, err
+		}
+		value.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(v, 10, typ.Bits())
+		if err != nil {
+			return value, err
+		}
+		value.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(v, typ.Bits())
+		if err != nil {
+			return value, err
+		}
+		value.SetFloat(f)
+	}
+	return value, nil
+}
+
+// conversionMessage returns the element error for a value which could not be
+// converted to typ, "" for fields no value can be mapped to.
+func conversionMessage(typ reflect.Type, err error) string {
+	if errors.Is(err, ErrUnsupportedField) {
+		return ""
+	}
+	if errors.Is(err, strconv.ErrRange) {
+		return "Value is out of range"
+	}
+	if typ == timeType || isTimestamp(typ) {
+		return "Value must be a valid date"
+	}
+	if _, ok := enumNames(typ); ok {
+		return "Value is not valid"
+	}
+	switch typ.Kind() {
+	case reflect.Bool:
+		return "Value must be true or false"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "Value must be a whole number"
+	case reflect.Float32, reflect.Float64:
+		return "Value must be a number"
+	case reflect.Slice:
+		return conversionMessage(typ.Elem(), err)
+	case reflect.Struct:
+		if isWrapperMessage(typ) {
+			index, _ := wrapperField(typ)
+			return conversionMessage(typ.Field(index).Type, err)
+		}
+	}
+	return "Value is not valid"
+}
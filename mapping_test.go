@@ -0,0 +1,65 @@
+package goform
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestMapToPointers(t *testing.T) {
+	type model struct {
+		Age  *int    `goform:"age"`
+		Name *string `goform:"name"`
+	}
+	age, name := 30, "Semih"
+	m := model{Age: &age, Name: &name}
+	form, err := NewFormFromStruct(&model{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form.bindValues(url.Values{"age": {""}, "name": {"Ali"}})
+	if err := form.MapTo(&m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Age != nil {
+		t.Errorf("age = %d, want nil", *m.Age)
+	}
+	if m.Name == nil || *m.Name != "Ali" {
+		t.Errorf("name = %v, want Ali", m.Name)
+	}
+}
+
+func TestMapToErrorsUntilValidated(t *testing.T) {
+	type model struct {
+		Age int `goform:"age"`
+	}
+	form, err := NewFormFromStruct(&model{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	age, _ := form.Get("age")
+
+	form.bindValues(url.Values{"age": {"abc"}})
+	var m model
+	for i := 0; i < 2; i++ {
+		var fieldErrors FieldErrors
+		if err := form.MapTo(&m); !errors.As(err, &fieldErrors) || len(fieldErrors) != 1 {
+			t.Fatalf("MapTo() = %v, want one field error", err)
+		}
+	}
+	if !form.HasError() || len(age.GetErrors()) != 1 {
+		t.Errorf("errors = %v, want one", age.GetErrors())
+	}
+
+	form.bindValues(url.Values{"age": {"30"}})
+	if !form.IsValid() {
+		t.Fatalf("IsValid() = false: %v", form.GetErrorMessages())
+	}
+	if err := form.MapTo(&m); err != nil || m.Age != 30 {
+		t.Errorf("MapTo() = %v, age %d, want 30", err, m.Age)
+	}
+	if form.HasError() || len(age.GetErrors()) != 0 {
+		t.Errorf("errors = %v, want none", age.GetErrors())
+	}
+}
@@ -0,0 +1,204 @@
+package goform
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Protobuf wrapper messages, timestamps and enums are detected by the shape of
+// the generated types, so they are mapped without depending on the protobuf
+// module.
+
+var (
+	ErrUnknownEnumValue = errors.New("Unknown enum value")
+
+	enumsMu sync.RWMutex
+	enums   = map[reflect.Type]map[int32]string{}
+)
+
+// RegisterEnum registers the names of the values of an enum type, e.g.
+// RegisterEnum(pb.Status(0), pb.Status_name). Enums are converted from and to
+// their names, numbers are accepted as well. Enums generated with descriptors
+// by protoc-gen-go are found without registering them.
+func RegisterEnum(v interface{}, names map[int32]string) {
+	enumsMu.Lock()
+	defer enumsMu.Unlock()
+	enums[reflect.TypeOf(v)] = names
+}
+
+// enumNames returns the names of the values of an int32 enum type, registered
+// with RegisterEnum or read from the Descriptor method of a generated enum.
+func enumNames(typ reflect.Type) (map[int32]string, bool) {
+	if typ.Kind() != reflect.Int32 {
+		return nil, false
+	}
+	enumsMu.RLock()
+	names, ok := enums[typ]
+	enumsMu.RUnlock()
+	if ok {
+		return names, names != nil
+	}
+
+	names = descriptorEnumNames(typ)
+	enumsMu.Lock()
+	enums[typ] = names
+	enumsMu.Unlock()
+	return names, names != nil
+}
+
+// descriptorEnumNames reads the names from the protoreflect.EnumDescriptor
+// of a generated enum, nil if typ has no such descriptor.
+func descriptorEnumNames(typ reflect.Type) (names map[int32]string) {
+	defer func() {
+		if recover() != nil {
+			names = nil
+		}
+	}()
+	values := callMethod(callMethod(reflect.Zero(typ), "Descriptor"), "Values")
+	length := callMethod(values, "Len")
+	if !length.IsValid() || length.Kind() != reflect.Int {
+		return nil
+	}
+	names = map[int32]string{}
+	for i := 0; i < int(length.Int()); i++ {
+		value := callMethod(values, "Get", reflect.ValueOf(i))
+		name, number := callMethod(value, "Name"), callMethod(value, "Number")
+		if name.Kind() != reflect.String || number.Kind() != reflect.Int32 {
+			return nil
+		}
+		names[int32(number.Int())] = name.String()
+	}
+	return names
+}
+
+// callMethod calls the method name of v with args, it returns an invalid value
+// if v has no such method returning one value.
+func callMethod(v reflect.Value, name string, args ...reflect.Value) reflect.Value {
+	if !v.IsValid() {
+		return reflect.Value{}
+	}
+	method := v.MethodByName(name)
+	if !method.IsValid() || method.Type().NumIn() != len(args) || method.Type().NumOut() != 1 {
+		return reflect.Value{}
+	}
+	return method.Call(args)[0]
+}
+
+func parseEnum(v string, typ reflect.Type, names map[int32]string) (reflect.Value, error) {
+	value := reflect.New(typ).Elem()
+	for number, name := range names {
+		if name == v {
+			value.SetInt(int64(number))
+			return value, nil
+		}
+	}
+	number, err := strconv.ParseInt(v, 10, 32)
+	if err != nil {
+		return value, ErrUnknownEnumValue
+	}
+	if _, ok := names[int32(number)]; !ok {
+		return value, ErrUnknownEnumValue
+	}
+	value.SetInt(number)
+	return value, nil
+}
+
+func formatEnum(value reflect.Value, names map[int32]string) string {
+	if name, ok := names[int32(value.Int())]; ok {
+		return name
+	}
+	return strconv.FormatInt(value.Int(), 10)
+}
+
+// enumValueOptions returns the values of an enum ordered by number.
+func enumValueOptions(names map[int32]string) []*ValueOption {
+	numbers := make([]int, 0, len(names))
+	for number := range names {
+		numbers = append(numbers, int(number))
+	}
+	sort.Ints(numbers)
+	valueOptions := make([]*ValueOption, 0, len(numbers))
+	for _, number := range numbers {
+		name := names[int32(number)]
+		valueOptions = append(valueOptions, &ValueOption{Value: name, Label: name})
+	}
+	return valueOptions
+}
+
+// messageFields returns the indexes of the exported fields of a struct without
+// the XXX_ fields of messages generated by older protobuf versions.
+func messageFields(typ reflect.Type) []int {
+	var fields []int
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" || strings.HasPrefix(field.Name, "XXX_") {
+			continue
+		}
+		fields = append(fields, i)
+	}
+	return fields
+}
+
+// wrapperField returns the index of the value of a wrapper type, a struct with
+// one exported field like wrapperspb.StringValue. Use isWrapperMessage to tell
+// wrapper messages from other structs of that shape.
+func wrapperField(typ reflect.Type) (int, bool) {
+	if typ.Kind() != reflect.Struct {
+		return 0, false
+	}
+	fields := messageFields(typ)
+	if len(fields) != 1 {
+		return 0, false
+	}
+	return fields[0], true
+}
+
+// isWrapperMessage reports whether typ is a generated wrapper message, the
+// value of which carries a protobuf tag.
+func isWrapperMessage(typ reflect.Type) bool {
+	index, ok := wrapperField(typ)
+	return ok && typ.Field(index).Tag.Get("protobuf") != ""
+}
+
+// isTimestamp reports whether typ has the Seconds and Nanos and the AsTime
+// method of timestamppb.Timestamp, which durationpb.Duration does not have.
+func isTimestamp(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Struct || len(messageFields(typ)) != 2 {
+		return false
+	}
+	seconds, ok := typ.FieldByName("Seconds")
+	if !ok || seconds.Type.Kind() != reflect.Int64 {
+		return false
+	}
+	nanos, ok := typ.FieldByName("Nanos")
+	if !ok || nanos.Type.Kind() != reflect.Int32 {
+		return false
+	}
+	asTime, ok := reflect.PtrTo(typ).MethodByName("AsTime")
+	return ok && asTime.Type.NumIn() == 1 && asTime.Type.NumOut() == 1 && asTime.Type.Out(0) == timeType
+}
+
+// timestampTime returns the time of a timestamp, the zero timestamp is the zero
+// time.
+func timestampTime(value reflect.Value) time.Time {
+	seconds, nanos := value.FieldByName("Seconds").Int(), value.FieldByName("Nanos").Int()
+	if seconds == 0 && nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(seconds, nanos).UTC()
+}
+
+func timestampValue(typ reflect.Type, t time.Time) reflect.Value {
+	value := reflect.New(typ).Elem()
+	if t.IsZero() {
+		return value
+	}
+	value.FieldByName("Seconds").SetInt(t.Unix())
+	value.FieldByName("Nanos").SetInt(int64(t.Nanosecond()))
+	return value
+}
@@ -0,0 +1,121 @@
+package goform
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type pbStringValue struct {
+	sizeCache int32
+	Value     string `protobuf:"bytes,1,opt,name=value,proto3"`
+}
+
+type pbTimestamp struct {
+	sizeCache int32
+	Seconds   int64 `protobuf:"varint,1,opt,name=seconds,proto3"`
+	Nanos     int32 `protobuf:"varint,2,opt,name=nanos,proto3"`
+}
+
+func (x *pbTimestamp) AsTime() time.Time {
+	return time.Unix(x.Seconds, int64(x.Nanos)).UTC()
+}
+
+type pbDuration struct {
+	sizeCache int32
+	Seconds   int64 `protobuf:"varint,1,opt,name=seconds,proto3"`
+	Nanos     int32 `protobuf:"varint,2,opt,name=nanos,proto3"`
+}
+
+func (x *pbDuration) AsDuration() time.Duration {
+	return time.Duration(x.Seconds)*time.Second + time.Duration(x.Nanos)
+}
+
+// lookalikes of the shapes which are no messages
+type money struct {
+	Amount int
+}
+
+type span struct {
+	Seconds int64
+	Nanos   int32
+}
+
+func TestProtoShapes(t *testing.T) {
+	tests := []struct {
+		value       interface{}
+		wrapper     bool
+		timestamp   bool
+		elementType string
+	}{
+		{pbStringValue{}, true, false, "text"},
+		{pbTimestamp{}, false, true, "date"},
+		{pbDuration{}, false, false, "subform"},
+		{money{}, false, false, "subform"},
+		{span{}, false, false, "subform"},
+	}
+	for _, test := range tests {
+		typ := reflect.TypeOf(test.value)
+		if got := isWrapperMessage(typ); got != test.wrapper {
+			t.Errorf("isWrapperMessage(%s) = %v, want %v", typ, got, test.wrapper)
+		}
+		if got := isTimestamp(typ); got != test.timestamp {
+			t.Errorf("isTimestamp(%s) = %v, want %v", typ, got, test.timestamp)
+		}
+		if got := inferElementType(typ, structTag{}); got != test.elementType {
+			t.Errorf("inferElementType(%s) = %q, want %q", typ, got, test.elementType)
+		}
+	}
+}
+
+func TestConvertLookalikes(t *testing.T) {
+	var chain converterChain
+	field := NewTextElement("value", "Value", nil, nil, nil)
+
+	wrapped, err := chain.parse(field, "x", reflect.TypeOf(pbStringValue{}))
+	if err != nil || wrapped.Interface().(pbStringValue).Value != "x" {
+		t.Errorf("parse wrapper = %v, %v, want x", wrapped, err)
+	}
+	if s, err := chain.format(reflect.ValueOf(pbStringValue{Value: "x"})); err != nil || s != "x" {
+		t.Errorf("format wrapper = %q, %v, want x", s, err)
+	}
+
+	for _, value := range []interface{}{money{Amount: 5}, span{Seconds: 5}, pbDuration{Seconds: 5}} {
+		typ := reflect.TypeOf(value)
+		if _, err := chain.parse(field, "5", typ); !errors.Is(err, ErrUnsupportedField) {
+			t.Errorf("parse %s = %v, want %v", typ, err, ErrUnsupportedField)
+		}
+		if s, err := chain.format(reflect.ValueOf(value)); !errors.Is(err, ErrUnsupportedField) {
+			t.Errorf("format %s = %q, %v, want %v", typ, s, err, ErrUnsupportedField)
+		}
+		if message := conversionMessage(typ, errors.New("invalid")); message != "Value is not valid" {
+			t.Errorf("conversionMessage(%s) = %q, want %q", typ, message, "Value is not valid")
+		}
+	}
+}
+
+func TestMapTimestamp(t *testing.T) {
+	type model struct {
+		Born pbTimestamp `goform:"born"`
+		Wait pbDuration
+	}
+	form, err := NewFormFromStruct(&model{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	born, _ := form.Get("born")
+	born.SetValue("2020-01-02")
+	wait, _ := form.Get("wait")
+	if wait.GetType() != ElementTypeSubForm {
+		t.Fatalf("wait is %s, want %s", wait.GetType(), ElementTypeSubForm)
+	}
+
+	var m model
+	if err := form.MapTo(&m); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Born.AsTime(); !got.Equal(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("born = %v, want 2020-01-02", got)
+	}
+}
@@ -35,7 +35,10 @@ func main() {
 		form.BindFromRequest(r)
 
 		s := YourStruct{}
-		form.MapTo(&s)
+		if err := form.MapTo(&s); err != nil {
+			tpl.Execute(w, form)
+			return
+		}
 		fmt.Println(s)
 	})
 	http.ListenAndServe(":2626", nil)
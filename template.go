@@ -2,25 +2,95 @@ package goform
 
 import (
 	"bytes"
-	"text/template"
+	"container/list"
+	"fmt"
+	"html/template"
+	"io"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template/parse"
+	"unsafe"
 )
 
 type Theme string
 
+// unsafeUrl replaces URLs with a scheme that could execute script, matching
+// the placeholder html/template uses for the same purpose.
+const unsafeUrl = "#ZgotmplZ"
+
+var (
+	attributeName = regexp.MustCompile(`^[a-zA-Z_:][-a-zA-Z0-9_:.]*$`)
+	urlAttributes = map[string]bool{
+		"action":     true,
+		"formaction": true,
+		"href":       true,
+		"src":        true,
+		"poster":     true,
+		"cite":       true,
+		"background": true,
+		"codebase":   true,
+		"data":       true,
+		"longdesc":   true,
+		"manifest":   true,
+		"ping":       true,
+		"usemap":     true,
+		"xlink:href": true,
+	}
+	// scriptAttributes hold script or markup and are only rendered when they
+	// are trusted, as are the on* event handlers
+	scriptAttributes = map[string]bool{
+		"srcdoc": true,
+		"srcset": true,
+	}
+	// styleTemplate writes style declarations in the CSS context of a style
+	// attribute, sanitizeStyle cuts the attribute value out of it
+	styleTemplate = template.Must(template.New("style").Parse(
+		`<p style="{{range $i, $d := .}}{{if $i}} {{end}}{{$d.Property}}: {{$d.Value}};{{end}}">`))
+
+	defaultTemplateFunctions = template.FuncMap{
+		"attributes": renderAttributes,
+	}
+)
+
 // https://v4-alpha.getbootstrap.com/components/forms/
 var ThemeBootstrap4alpha6Inline Theme = `
+{{define "form"}}
+<form action="{{.GetAction}}" method="{{.GetMethod}}"{{if .GetId}} id="{{.GetId}}"{{end}}{{if and .IsMultipart (not (.HasAttribute "enctype"))}} enctype="multipart/form-data"{{end}}{{if .IsNoValidate}} novalidate{{end}}{{attributes .GetAttributes}}
+      {{if not (.HasAttribute "class")}}class="form-inline"{{end}}>
+{{template "formerrors" .}}
+{{.RenderElements}}
+</form>
+{{end}}
+
+{{define "formerrors"}}
+{{if .GetFormErrors}}
+<div class="alert alert-danger" role="alert">
+    <ul class="mb-0">
+        {{range .GetFormErrorMessages}}
+        <li>{{.}}</li>
+        {{end}}
+    </ul>
+</div>
+{{end}}
+{{end}}
+
 {{define "text"}}
 <div class="form-group mr-2 {{if .GetErrors}}has-danger has-feedback{{end}}">
-    <label for="id_{{.Name}}" class="mr-2">{{.Label}}</label>
-    <input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}" {{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}
+    <label for="id_{{.Name}}" class="mr-2">{{.RenderLabel}}</label>
+    <input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}" {{attributes .Attributes .GetConstraintAttributes}}
            {{if not (.HasAttribute "class")}}class="form-control"{{end}}
     id="id_{{.Name}}" />
 
     {{if .GetErrors}}
     <div class="form-control-feedback d-block w-100">
         <ul>
-            {{range .GetErrors}}
-            <li>{{.Message}}</li>
+            {{range .GetErrorMessages}}
+            <li>{{.}}</li>
             {{end}}
         </ul>
     </div>
@@ -30,7 +100,7 @@ var ThemeBootstrap4alpha6Inline Theme = `
 
 {{define "file"}}
 <div class="form-group mr-2 {{if .GetErrors}}has-danger{{end}}">
-    <label for="id_{{.Name}}" class="mr-2">{{.Label}}</label>
+    <label for="id_{{.Name}}" class="mr-2">{{.RenderLabel}}</label>
     <label class="custom-file">
 
     {{if .GetFile}}
@@ -41,7 +111,7 @@ var ThemeBootstrap4alpha6Inline Theme = `
     </a>
     {{end}}
     {{end}}
-        <input id="id_{{.Name}}" name="{{.Name}}" type="{{.Type}}" value="{{.Value}}" {{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}
+        <input id="id_{{.Name}}" name="{{.Name}}" type="file"{{if .IsMultiple}} multiple{{end}} value="{{.Value}}" {{attributes .Attributes .GetConstraintAttributes}}
                {{if not (.HasAttribute "class")}}class="custom-file-input"{{end}}>
         <span class="custom-file-control"></span>
     </label>
@@ -49,21 +119,22 @@ var ThemeBootstrap4alpha6Inline Theme = `
 {{end}}
 
 {{define "hidden"}}
-<input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}} id="id_{{.Name}}" />
+<input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}"{{attributes .Attributes}} id="id_{{.Name}}" />
 {{end}}
 
 {{define "textarea"}}
 <div class="form-group mr-2 {{if .GetErrors}}has-danger{{end}}">
-    <label for="id_{{.Name}}" class="mr-2">{{.Label}}</label>
-    <textarea name="{{.Name}}"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}
+    <label for="id_{{.Name}}" class="mr-2">{{.RenderLabel}}</label>
+    <textarea name="{{.Name}}"{{attributes .Attributes .GetConstraintAttributes}}
           {{if not (.HasAttribute "class")}}class="form-control"{{end}}
-    id="id_{{.Name}}">{{.Value}}</textarea>
+    id="id_{{.Name}}">
+{{.Value}}</textarea>
 
     {{if .GetErrors}}
     <div class="form-control-feedback d-block w-100">
         <ul>
-            {{range .GetErrors}}
-            <li>{{.Message}}</li>
+            {{range .GetErrorMessages}}
+            <li>{{.}}</li>
             {{end}}
         </ul>
     </div>
@@ -73,8 +144,8 @@ var ThemeBootstrap4alpha6Inline Theme = `
 
 {{define "select"}}
 <div class="form-group mr-2 {{if .GetErrors}}has-danger{{end}}">
-    <label for="id_{{.Name}}" class="mr-2">{{.Label}}</label>
-    <select name="{{.Name}}"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}
+    <label for="id_{{.Name}}" class="mr-2">{{.RenderLabel}}</label>
+    <select name="{{.Name}}"{{if .IsMultiple}} multiple{{end}}{{attributes .Attributes .GetConstraintAttributes}}
             {{if not (.HasAttribute "class")}}class="form-control"{{end}}
     id="id_{{.Name}}">
     {{range .ValueOptions}}
@@ -85,8 +156,8 @@ var ThemeBootstrap4alpha6Inline Theme = `
     {{if .GetErrors}}
     <div class="form-control-feedback d-block w-100">
         <ul>
-            {{range .GetErrors}}
-            <li>{{.Message}}</li>
+            {{range .GetErrorMessages}}
+            <li>{{.}}</li>
             {{end}}
         </ul>
     </div>
@@ -96,12 +167,12 @@ var ThemeBootstrap4alpha6Inline Theme = `
 
 {{define "radio"}}
 <div class="form-group mr-2 {{if .GetErrors}}has-danger{{end}}">
-    <label class="mr-2 d-block">{{.Label}}</label>
-    {{range .ValueOptions}}
+    <label class="mr-2 d-block">{{.RenderLabel}}</label>
+    {{range $index, $option := .ValueOptions}}
     <label class="custom-control custom-radio">
-        <input type="radio" name="{{$.Name}}" value="{{.Value}}" id="{{$.Name}}" class="custom-control-input"
+        <input type="radio" name="{{$.Name}}" value="{{.Value}}" id="{{$.Name}}{{$index}}" class="custom-control-input"
                {{if eq $.Value .Value}} checked {{else}} {{if .Selected}} checked {{end}} {{end}}
-               {{if .Disabled}} disabled{{end}} />
+               {{if .Disabled}} disabled{{end}}{{attributes $.GetConstraintAttributes}} />
         <span class="custom-control-indicator"></span>
         <span class="custom-control-description">{{.Label}}</span>
     </label>
@@ -110,8 +181,8 @@ var ThemeBootstrap4alpha6Inline Theme = `
     {{if .GetErrors}}
     <div class="form-control-feedback d-block w-100">
         <ul>
-            {{range .GetErrors}}
-            <li>{{.Message}}</li>
+            {{range .GetErrorMessages}}
+            <li>{{.}}</li>
             {{end}}
         </ul>
     </div>
@@ -121,10 +192,10 @@ var ThemeBootstrap4alpha6Inline Theme = `
 
 {{define "multicheckbox"}}
 <div class="form-group mr-2 {{if .GetErrors}}has-danger{{end}}">
-    <label class="mr-2 d-block">{{.Label}}</label>
-    {{range .ValueOptions}}
+    <label class="mr-2 d-block">{{.RenderLabel}}</label>
+    {{range $index, $option := .ValueOptions}}
     <label class="custom-control custom-checkbox">
-        <input type="checkbox" class="custom-control-input" name="{{$.Name}}[]" value="{{.Value}}" id="{{$.Name}}" class="custom-control-input"
+        <input type="checkbox" class="custom-control-input" name="{{$.Name}}[]" value="{{.Value}}" id="{{$.Name}}{{$index}}" class="custom-control-input"
                {{if $.IsCheckedInValues .Value}}
                checked
                {{else}}
@@ -139,8 +210,8 @@ var ThemeBootstrap4alpha6Inline Theme = `
     {{if .GetErrors}}
     <div class="form-control-feedback d-block w-100">
         <ul>
-            {{range .GetErrors}}
-            <li>{{.Message}}</li>
+            {{range .GetErrorMessages}}
+            <li>{{.}}</li>
             {{end}}
         </ul>
     </div>
@@ -155,14 +226,14 @@ var ThemeBootstrap4alpha6Inline Theme = `
         <input type="checkbox" class="custom-control-input" name="{{.Name}}" value="true" id="{{.Name}}" class="custom-control-input"
                {{if .IsChecked}} checked{{end}}/>
         <span class="custom-control-indicator"></span>
-        <span class="custom-control-description">{{.Label}}</span>
+        <span class="custom-control-description">{{.RenderLabel}}</span>
     </label>
 
     {{if .GetErrors}}
     <div class="form-control-feedback d-block w-100">
         <ul>
-            {{range .GetErrors}}
-            <li>{{.Message}}</li>
+            {{range .GetErrorMessages}}
+            <li>{{.}}</li>
             {{end}}
         </ul>
     </div>
@@ -172,39 +243,74 @@ var ThemeBootstrap4alpha6Inline Theme = `
 
 {{define "button"}}
 <div class="form-group mr-2">
-    <button type="submit" class="btn btn-primary ml-2"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}} style="min-width: 200px;">{{.Label}}</button>
+    <button type="submit" class="btn btn-primary ml-2"{{attributes .Attributes}} style="min-width: 200px;">{{.RenderLabel}}</button>
 </div>
 {{end}}
 
 {{define "submit"}}
 <div class="form-group mr-2">
-    <input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="submit" class="btn btn-primary ml-2"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}} value="{{.Label}}" style="min-width: 200px;">
+    <input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="submit" class="btn btn-primary ml-2"{{attributes .Attributes}} value="{{.Label}}" style="min-width: 200px;">
 </div>
 {{end}}
 
 {{define "image"}}
 <div class="form-group mr-2">
-    <input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="image" {{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}>
+    <input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="image" {{attributes .Attributes}}>
 </div>
 {{end}}
 
+{{define "captcha"}}
+{{$challenge := .GetChallenge}}
+<div class="form-group mr-2 {{if .GetErrors}}has-danger has-feedback{{end}}">
+    <label for="id_{{.Name}}" class="mr-2">{{.RenderLabel}}</label>
+    {{$challenge.Widget}}
+    {{range $challenge.Hidden}}<input type="hidden" name="{{.Key}}" value="{{.Value}}" />{{end}}
+    {{if $challenge.Answer}}
+    <input name="{{.Name}}" type="text" value="" autocomplete="off" {{attributes .Attributes .GetConstraintAttributes}}
+           {{if not (.HasAttribute "class")}}class="form-control"{{end}}
+    id="id_{{.Name}}" />
+    {{end}}
+
+    {{if .GetErrors}}
+    <div class="form-control-feedback d-block w-100">
+        <ul>
+            {{range .GetErrorMessages}}
+            <li>{{.}}</li>
+            {{end}}
+        </ul>
+    </div>
+    {{end}}
+</div>
+{{end}}
 `
 
 // https://v4-alpha.getbootstrap.com/components/forms/
 var ThemeBootstrap4alpha6Textual Theme = `
+{{define "formerrors"}}
+{{if .GetFormErrors}}
+<div class="alert alert-danger" role="alert">
+    <ul class="mb-0">
+        {{range .GetFormErrorMessages}}
+        <li>{{.}}</li>
+        {{end}}
+    </ul>
+</div>
+{{end}}
+{{end}}
+
 {{define "text"}}
 <div class="form-group row {{if .GetErrors}}has-danger{{end}}">
-    <label for="id_{{.Name}}" class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.Label}}</label>
+    <label for="id_{{.Name}}" class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.RenderLabel}}</label>
     <div class="col-xl-10 col-lg-9 col-md-12">
-    <input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}" {{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}
+    <input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}" {{attributes .Attributes .GetConstraintAttributes}}
     {{if not (.HasAttribute "class")}}class="form-control"{{end}}
     id="id_{{.Name}}" />
 
     {{if .GetErrors}}
     <div class="form-control-feedback">
     <ul>
-    {{range .GetErrors}}
-    <li>{{.Message}}</li>
+    {{range .GetErrorMessages}}
+    <li>{{.}}</li>
     {{end}}
     </ul>
     </div>
@@ -216,7 +322,7 @@ var ThemeBootstrap4alpha6Textual Theme = `
 
 {{define "file"}}
 <div class="form-group row {{if .GetErrors}}has-danger{{end}}">
-    <label for="id_{{.Name}}" class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.Label}}</label>
+    <label for="id_{{.Name}}" class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.RenderLabel}}</label>
     <div class="col-xl-10 col-lg-9 col-md-12">
     {{if .GetFile}}
     <a href="{{.GetFile.Location}}" target="_blank">{{.GetFile.Name}}</a>
@@ -227,7 +333,7 @@ var ThemeBootstrap4alpha6Textual Theme = `
     {{end}}
     {{end}}
     <div class="custom-file w-100">
-  	<input id="id_{{.Name}}" name="{{.Name}}" type="{{.Type}}" value="{{.Value}}" {{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}
+  	<input id="id_{{.Name}}" name="{{.Name}}" type="file"{{if .IsMultiple}} multiple{{end}} value="{{.Value}}" {{attributes .Attributes .GetConstraintAttributes}}
   	{{if not (.HasAttribute "class")}}class="custom-file-input"{{end}}>
         <label class="custom-file-label"></label>
     </div>
@@ -236,22 +342,23 @@ var ThemeBootstrap4alpha6Textual Theme = `
 {{end}}
 
 {{define "hidden"}}
-<input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}} id="id_{{.Name}}" />
+<input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}"{{attributes .Attributes}} id="id_{{.Name}}" />
 {{end}}
 
 {{define "textarea"}}
 <div class="form-group row {{if .GetErrors}}has-danger{{end}}">
-    <label for="id_{{.Name}}" class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.Label}}</label>
+    <label for="id_{{.Name}}" class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.RenderLabel}}</label>
     <div class="col-xl-10 col-lg-9 col-md-12">
-    <textarea name="{{.Name}}"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}
+    <textarea name="{{.Name}}"{{attributes .Attributes .GetConstraintAttributes}}
     {{if not (.HasAttribute "class")}}class="form-control"{{end}}
-    id="id_{{.Name}}">{{.Value}}</textarea>
+    id="id_{{.Name}}">
+{{.Value}}</textarea>
 
     {{if .GetErrors}}
     <div class="form-control-feedback">
     <ul>
-    {{range .GetErrors}}
-    <li>{{.Message}}</li>
+    {{range .GetErrorMessages}}
+    <li>{{.}}</li>
     {{end}}
     </ul>
     </div>
@@ -263,9 +370,9 @@ var ThemeBootstrap4alpha6Textual Theme = `
 
 {{define "select"}}
 <div class="form-group row {{if .GetErrors}}has-danger{{end}}">
-    <label for="id_{{.Name}}" class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.Label}}</label>
+    <label for="id_{{.Name}}" class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.RenderLabel}}</label>
     <div class="col-xl-10 col-lg-9 col-md-12">
-    <select name="{{.Name}}"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}
+    <select name="{{.Name}}"{{if .IsMultiple}} multiple{{end}}{{attributes .Attributes .GetConstraintAttributes}}
     {{if not (.HasAttribute "class")}}class="form-control"{{end}}
     id="id_{{.Name}}">
       {{range .ValueOptions}}
@@ -276,8 +383,8 @@ var ThemeBootstrap4alpha6Textual Theme = `
     {{if .GetErrors}}
     <div class="form-control-feedback">
     <ul>
-    {{range .GetErrors}}
-    <li>{{.Message}}</li>
+    {{range .GetErrorMessages}}
+    <li>{{.}}</li>
     {{end}}
     </ul>
     </div>
@@ -289,13 +396,13 @@ var ThemeBootstrap4alpha6Textual Theme = `
 
 {{define "radio"}}
 <div class="form-group row {{if .GetErrors}}has-danger{{end}}">
-    <label class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.Label}}</label>
+    <label class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.RenderLabel}}</label>
     <div class="col-xl-10 col-lg-9 col-md-12">
-	{{range .ValueOptions}}
+	{{range $index, $option := .ValueOptions}}
 	  <label class="custom-control custom-radio custom-control-inline">
-	    <input type="radio" name="{{$.Name}}" value="{{.Value}}" id="{{$.Name}}" class="custom-control-input"
+	    <input type="radio" name="{{$.Name}}" value="{{.Value}}" id="{{$.Name}}{{$index}}" class="custom-control-input"
 		     {{if eq $.Value .Value}} checked {{else}} {{if .Selected}} checked {{end}} {{end}}
-		     {{if .Disabled}} disabled{{end}}{{range $.Attributes}} {{.Key}}="{{.Value}}"{{end}} />
+		     {{if .Disabled}} disabled{{end}}{{attributes $.Attributes $.GetConstraintAttributes}} />
 	    <span class="custom-control-label">{{.Label}}</span>
 	  </label>
 	{{end}}
@@ -303,8 +410,8 @@ var ThemeBootstrap4alpha6Textual Theme = `
 	    {{if .GetErrors}}
 	    <div class="form-control-feedback">
 	    <ul>
-	    {{range .GetErrors}}
-	    <li>{{.Message}}</li>
+	    {{range .GetErrorMessages}}
+	    <li>{{.}}</li>
 	    {{end}}
 	    </ul>
 	    </div>
@@ -315,7 +422,7 @@ var ThemeBootstrap4alpha6Textual Theme = `
 
 {{define "multicheckbox"}}
 <div class="form-group row {{if .GetErrors}}has-danger{{end}}">
-    <label class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.Label}}</label>
+    <label class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.RenderLabel}}</label>
     <div class="col-xl-10 col-lg-9 col-md-12">
     {{range .ValueOptions}}
     <div class="custom-control custom-checkbox">
@@ -333,8 +440,8 @@ var ThemeBootstrap4alpha6Textual Theme = `
     {{if .GetErrors}}
     <div class="form-control-feedback">
     <ul>
-    {{range .GetErrors}}
-    <li>{{.Message}}</li>
+    {{range .GetErrorMessages}}
+    <li>{{.}}</li>
     {{end}}
     </ul>
     </div>
@@ -351,14 +458,14 @@ var ThemeBootstrap4alpha6Textual Theme = `
     <input type="checkbox" class="custom-control-input" name="{{.Name}}" value="true" id="{{.Name}}" class="custom-control-input"
      {{if .IsChecked}} checked{{end}}/>
     <span class="custom-control-indicator"></span>
-    <span class="custom-control-description">{{.Label}}</span>
+    <span class="custom-control-description">{{.RenderLabel}}</span>
   </label>
 
     {{if .GetErrors}}
     <div class="form-control-feedback">
     <ul>
-    {{range .GetErrors}}
-    <li>{{.Message}}</li>
+    {{range .GetErrorMessages}}
+    <li>{{.}}</li>
     {{end}}
     </ul>
     </div>
@@ -370,7 +477,7 @@ var ThemeBootstrap4alpha6Textual Theme = `
 {{define "button"}}
 <div class="form-group row">
 <div class="offset-xl-2 offset-lg-3">
-<button type="submit" class="btn btn-primary ml-3"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}} style="min-width: 200px;">{{.Label}}</button>
+<button type="submit" class="btn btn-primary ml-3"{{attributes .Attributes}} style="min-width: 200px;">{{.RenderLabel}}</button>
 </div>
 </div>
 {{end}}
@@ -378,7 +485,7 @@ var ThemeBootstrap4alpha6Textual Theme = `
 {{define "submit"}}
 <div class="form-group row">
 <div class="offset-xl-2 offset-lg-3">
-<input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="submit" class="btn btn-primary ml-3"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}} value="{{.Label}}" style="min-width: 200px;">
+<input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="submit" class="btn btn-primary ml-3"{{attributes .Attributes}} value="{{.Label}}" style="min-width: 200px;">
 </div>
 </div>
 {{end}}
@@ -386,26 +493,64 @@ var ThemeBootstrap4alpha6Textual Theme = `
 {{define "image"}}
 <div class="form-group row">
 <div class="offset-xl-2 offset-lg-3">
-<input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="image" {{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}>
+<input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="image" {{attributes .Attributes}}>
 </div>
 </div>
 {{end}}
+{{define "captcha"}}
+{{$challenge := .GetChallenge}}
+<div class="form-group row {{if .GetErrors}}has-danger{{end}}">
+    <label for="id_{{.Name}}" class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.RenderLabel}}</label>
+    <div class="col-xl-10 col-lg-9 col-md-12">
+    {{$challenge.Widget}}
+    {{range $challenge.Hidden}}<input type="hidden" name="{{.Key}}" value="{{.Value}}" />{{end}}
+    {{if $challenge.Answer}}
+    <input name="{{.Name}}" type="text" value="" autocomplete="off" {{attributes .Attributes .GetConstraintAttributes}}
+    {{if not (.HasAttribute "class")}}class="form-control"{{end}}
+    id="id_{{.Name}}" />
+    {{end}}
+
+    {{if .GetErrors}}
+    <div class="form-control-feedback">
+    <ul>
+    {{range .GetErrorMessages}}
+    <li>{{.}}</li>
+    {{end}}
+    </ul>
+    </div>
+    {{end}}
+
+    </div>
+</div>
+{{end}}
 `
 
 // https://v4-alpha.getbootstrap.com/components/forms/
 var ThemeBootstrap4alpha6 Theme = `
+{{define "formerrors"}}
+{{if .GetFormErrors}}
+<div class="alert alert-danger" role="alert">
+    <ul class="mb-0">
+        {{range .GetFormErrorMessages}}
+        <li>{{.}}</li>
+        {{end}}
+    </ul>
+</div>
+{{end}}
+{{end}}
+
 {{define "text"}}
 <div class="form-group{{if .GetErrors}} has-danger{{end}}">
-    <label for="id_{{.Name}}">{{.Label}}</label>
-    <input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}" {{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}
+    <label for="id_{{.Name}}">{{.RenderLabel}}</label>
+    <input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}" {{attributes .Attributes .GetConstraintAttributes}}
     {{if not (.HasAttribute "class")}}class="form-control"{{end}}
     id="id_{{.Name}}" />
 
     {{if .GetErrors}}
     <div class="form-control-feedback">
     <ul>
-    {{range .GetErrors}}
-    <li>{{.Message}}</li>
+    {{range .GetErrorMessages}}
+    <li>{{.}}</li>
     {{end}}
     </ul>
     </div>
@@ -415,7 +560,7 @@ var ThemeBootstrap4alpha6 Theme = `
 
 {{define "file"}}
 <div class="form-group{{if .GetErrors}} has-danger{{end}}">
-    <label for="id_{{.Name}}">{{.Label}}</label>
+    <label for="id_{{.Name}}">{{.RenderLabel}}</label>
     <label class="custom-file w-100">
 
     {{if .GetFile}}
@@ -426,7 +571,7 @@ var ThemeBootstrap4alpha6 Theme = `
     </a>
     {{end}}
     {{end}}
-  	<input id="id_{{.Name}}" name="{{.Name}}" type="{{.Type}}" value="{{.Value}}" {{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}
+  	<input id="id_{{.Name}}" name="{{.Name}}" type="file"{{if .IsMultiple}} multiple{{end}} value="{{.Value}}" {{attributes .Attributes .GetConstraintAttributes}}
   	{{if not (.HasAttribute "class")}}class="custom-file-input"{{end}}>
         <span class="custom-file-control"></span>
     </label>
@@ -434,21 +579,22 @@ var ThemeBootstrap4alpha6 Theme = `
 {{end}}
 
 {{define "hidden"}}
-<input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}} id="id_{{.Name}}" />
+<input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}"{{attributes .Attributes}} id="id_{{.Name}}" />
 {{end}}
 
 {{define "textarea"}}
 <div class="form-group{{if .GetErrors}} has-danger{{end}}">
-    <label for="id_{{.Name}}">{{.Label}}</label>
-    <textarea name="{{.Name}}"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}
+    <label for="id_{{.Name}}">{{.RenderLabel}}</label>
+    <textarea name="{{.Name}}"{{attributes .Attributes .GetConstraintAttributes}}
     {{if not (.HasAttribute "class")}}class="form-control"{{end}}
-    id="id_{{.Name}}">{{.Value}}</textarea>
+    id="id_{{.Name}}">
+{{.Value}}</textarea>
 
     {{if .GetErrors}}
     <div class="form-control-feedback">
     <ul>
-    {{range .GetErrors}}
-    <li>{{.Message}}</li>
+    {{range .GetErrorMessages}}
+    <li>{{.}}</li>
     {{end}}
     </ul>
     </div>
@@ -459,8 +605,8 @@ var ThemeBootstrap4alpha6 Theme = `
 
 {{define "select"}}
 <div class="form-group{{if .GetErrors}} has-danger{{end}}">
-    <label for="id_{{.Name}}">{{.Label}}</label>
-    <select name="{{.Name}}"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}
+    <label for="id_{{.Name}}">{{.RenderLabel}}</label>
+    <select name="{{.Name}}"{{if .IsMultiple}} multiple{{end}}{{attributes .Attributes .GetConstraintAttributes}}
     {{if not (.HasAttribute "class")}}class="form-control"{{end}}
     id="id_{{.Name}}">
       {{range .ValueOptions}}
@@ -471,8 +617,8 @@ var ThemeBootstrap4alpha6 Theme = `
     {{if .GetErrors}}
     <div class="form-control-feedback">
     <ul>
-    {{range .GetErrors}}
-    <li>{{.Message}}</li>
+    {{range .GetErrorMessages}}
+    <li>{{.}}</li>
     {{end}}
     </ul>
     </div>
@@ -483,13 +629,13 @@ var ThemeBootstrap4alpha6 Theme = `
 
 {{define "radio"}}
 <div class="form-group{{if .GetErrors}} has-danger{{end}}">
-    <label>{{.Label}}</label>
+    <label>{{.RenderLabel}}</label>
     <div class="custom-controls-stacked">
-	{{range .ValueOptions}}
+	{{range $index, $option := .ValueOptions}}
 	  <label class="custom-control custom-radio">
-	    <input type="radio" name="{{$.Name}}" value="{{.Value}}" id="{{$.Name}}" class="custom-control-input"
+	    <input type="radio" name="{{$.Name}}" value="{{.Value}}" id="{{$.Name}}{{$index}}" class="custom-control-input"
 		     {{if eq $.Value .Value}} checked {{else}} {{if .Selected}} checked {{end}} {{end}}
-		     {{if .Disabled}} disabled{{end}} />
+		     {{if .Disabled}} disabled{{end}}{{attributes $.GetConstraintAttributes}} />
 	    <span class="custom-control-indicator"></span>
 	    <span class="custom-control-description">{{.Label}}</span>
 	  </label>
@@ -498,8 +644,8 @@ var ThemeBootstrap4alpha6 Theme = `
 	    {{if .GetErrors}}
 	    <div class="form-control-feedback">
 	    <ul>
-	    {{range .GetErrors}}
-	    <li>{{.Message}}</li>
+	    {{range .GetErrorMessages}}
+	    <li>{{.}}</li>
 	    {{end}}
 	    </ul>
 	    </div>
@@ -509,11 +655,11 @@ var ThemeBootstrap4alpha6 Theme = `
 
 {{define "multicheckbox"}}
 <div class="form-group{{if .GetErrors}} has-danger{{end}}">
-    <label>{{.Label}}</label>
+    <label>{{.RenderLabel}}</label>
     <div class="custom-controls-stacked">
-    {{range .ValueOptions}}
+    {{range $index, $option := .ValueOptions}}
     <label class="custom-control custom-checkbox">
-      <input type="checkbox" class="custom-control-input" name="{{$.Name}}[]" value="{{.Value}}" id="{{$.Name}}" class="custom-control-input"
+      <input type="checkbox" class="custom-control-input" name="{{$.Name}}[]" value="{{.Value}}" id="{{$.Name}}{{$index}}" class="custom-control-input"
       {{if $.IsCheckedInValues .Value}}
       checked
       {{else}}
@@ -529,8 +675,8 @@ var ThemeBootstrap4alpha6 Theme = `
     {{if .GetErrors}}
     <div class="form-control-feedback">
     <ul>
-    {{range .GetErrors}}
-    <li>{{.Message}}</li>
+    {{range .GetErrorMessages}}
+    <li>{{.}}</li>
     {{end}}
     </ul>
     </div>
@@ -545,14 +691,14 @@ var ThemeBootstrap4alpha6 Theme = `
     <input type="checkbox" class="custom-control-input" name="{{.Name}}" value="true" id="{{.Name}}" class="custom-control-input"
      {{if .IsChecked}} checked{{end}}/>
     <span class="custom-control-indicator"></span>
-    <span class="custom-control-description">{{.Label}}</span>
+    <span class="custom-control-description">{{.RenderLabel}}</span>
   </label>
 
     {{if .GetErrors}}
     <div class="form-control-feedback">
     <ul>
-    {{range .GetErrors}}
-    <li>{{.Message}}</li>
+    {{range .GetErrorMessages}}
+    <li>{{.}}</li>
     {{end}}
     </ul>
     </div>
@@ -562,37 +708,80 @@ var ThemeBootstrap4alpha6 Theme = `
 
 {{define "button"}}
 <div class="form-group">
-<button type="submit" class="btn btn-primary ml-2"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}} style="min-width: 200px;">{{.Label}}</button>
+<button type="submit" class="btn btn-primary ml-2"{{attributes .Attributes}} style="min-width: 200px;">{{.RenderLabel}}</button>
 </div>
 {{end}}
 
 {{define "submit"}}
 <div class="form-group">
-<input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="submit" class="btn btn-primary ml-2"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}} value="{{.Label}}" style="min-width: 200px;">
+<input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="submit" class="btn btn-primary ml-2"{{attributes .Attributes}} value="{{.Label}}" style="min-width: 200px;">
 </div>
 {{end}}
 
 {{define "image"}}
 <div class="form-group">
-<input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="image" {{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}>
+<input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="image" {{attributes .Attributes}}>
+</div>
+{{end}}
+{{define "captcha"}}
+{{$challenge := .GetChallenge}}
+<div class="form-group{{if .GetErrors}} has-danger{{end}}">
+    <label for="id_{{.Name}}">{{.RenderLabel}}</label>
+    {{$challenge.Widget}}
+    {{range $challenge.Hidden}}<input type="hidden" name="{{.Key}}" value="{{.Value}}" />{{end}}
+    {{if $challenge.Answer}}
+    <input name="{{.Name}}" type="text" value="" autocomplete="off" {{attributes .Attributes .GetConstraintAttributes}}
+    {{if not (.HasAttribute "class")}}class="form-control"{{end}}
+    id="id_{{.Name}}" />
+    {{end}}
+
+    {{if .GetErrors}}
+    <div class="form-control-feedback">
+    <ul>
+    {{range .GetErrorMessages}}
+    <li>{{.}}</li>
+    {{end}}
+    </ul>
+    </div>
+    {{end}}
 </div>
 {{end}}
 `
 
 // https://getbootstrap.com/components/forms/
 var ThemeBootstrap4Inline Theme = `
+{{define "form"}}
+<form action="{{.GetAction}}" method="{{.GetMethod}}"{{if .GetId}} id="{{.GetId}}"{{end}}{{if and .IsMultipart (not (.HasAttribute "enctype"))}} enctype="multipart/form-data"{{end}}{{if .IsNoValidate}} novalidate{{end}}{{attributes .GetAttributes}}
+      {{if not (.HasAttribute "class")}}class="form-inline"{{end}}>
+{{template "formerrors" .}}
+{{.RenderElements}}
+</form>
+{{end}}
+
+{{define "formerrors"}}
+{{if .GetFormErrors}}
+<div class="alert alert-danger" role="alert">
+    <ul class="mb-0">
+        {{range .GetFormErrorMessages}}
+        <li>{{.}}</li>
+        {{end}}
+    </ul>
+</div>
+{{end}}
+{{end}}
+
 {{define "text"}}
 <div class="form-group mr-3 {{if .GetErrors}}has-danger has-feedback{{end}}">
-    <label for="id_{{.Name}}" class="mr-3">{{.Label}}</label>
-    <input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}" {{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}
+    <label for="id_{{.Name}}" class="mr-3">{{.RenderLabel}}</label>
+    <input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}" {{attributes .Attributes .GetConstraintAttributes}}
            {{if not (.HasAttribute "class")}}class="form-control"{{end}}
     id="id_{{.Name}}" />
 
     {{if .GetErrors}}
     <div class="form-control-feedback d-block w-100">
         <ul>
-            {{range .GetErrors}}
-            <li>{{.Message}}</li>
+            {{range .GetErrorMessages}}
+            <li>{{.}}</li>
             {{end}}
         </ul>
     </div>
@@ -602,7 +791,7 @@ var ThemeBootstrap4Inline Theme = `
 
 {{define "file"}}
 <div class="form-group mr-3 {{if .GetErrors}}has-danger{{end}}">
-    <label for="id_{{.Name}}" class="mr-3">{{.Label}}</label>
+    <label for="id_{{.Name}}" class="mr-3">{{.RenderLabel}}</label>
     <label class="custom-file">
 
     {{if .GetFile}}
@@ -613,7 +802,7 @@ var ThemeBootstrap4Inline Theme = `
     </a>
     {{end}}
     {{end}}
-        <input id="id_{{.Name}}" name="{{.Name}}" type="{{.Type}}" value="{{.Value}}" {{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}
+        <input id="id_{{.Name}}" name="{{.Name}}" type="file"{{if .IsMultiple}} multiple{{end}} value="{{.Value}}" {{attributes .Attributes .GetConstraintAttributes}}
                {{if not (.HasAttribute "class")}}class="custom-file-input"{{end}}>
         <span class="custom-file-control"></span>
     </label>
@@ -621,21 +810,22 @@ var ThemeBootstrap4Inline Theme = `
 {{end}}
 
 {{define "hidden"}}
-<input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}} id="id_{{.Name}}" />
+<input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}"{{attributes .Attributes}} id="id_{{.Name}}" />
 {{end}}
 
 {{define "textarea"}}
 <div class="form-group mr-3 {{if .GetErrors}}has-danger{{end}}">
-    <label for="id_{{.Name}}" class="mr-3">{{.Label}}</label>
-    <textarea name="{{.Name}}"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}
+    <label for="id_{{.Name}}" class="mr-3">{{.RenderLabel}}</label>
+    <textarea name="{{.Name}}"{{attributes .Attributes .GetConstraintAttributes}}
           {{if not (.HasAttribute "class")}}class="form-control"{{end}}
-    id="id_{{.Name}}">{{.Value}}</textarea>
+    id="id_{{.Name}}">
+{{.Value}}</textarea>
 
     {{if .GetErrors}}
     <div class="form-control-feedback d-block w-100">
         <ul>
-            {{range .GetErrors}}
-            <li>{{.Message}}</li>
+            {{range .GetErrorMessages}}
+            <li>{{.}}</li>
             {{end}}
         </ul>
     </div>
@@ -645,8 +835,8 @@ var ThemeBootstrap4Inline Theme = `
 
 {{define "select"}}
 <div class="form-group mr-3 {{if .GetErrors}}has-danger{{end}}">
-    <label for="id_{{.Name}}" class="mr-3">{{.Label}}</label>
-    <select name="{{.Name}}"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}
+    <label for="id_{{.Name}}" class="mr-3">{{.RenderLabel}}</label>
+    <select name="{{.Name}}"{{if .IsMultiple}} multiple{{end}}{{attributes .Attributes .GetConstraintAttributes}}
             {{if not (.HasAttribute "class")}}class="form-control"{{end}}
     id="id_{{.Name}}">
     {{range .ValueOptions}}
@@ -657,8 +847,8 @@ var ThemeBootstrap4Inline Theme = `
     {{if .GetErrors}}
     <div class="form-control-feedback d-block w-100">
         <ul>
-            {{range .GetErrors}}
-            <li>{{.Message}}</li>
+            {{range .GetErrorMessages}}
+            <li>{{.}}</li>
             {{end}}
         </ul>
     </div>
@@ -668,12 +858,12 @@ var ThemeBootstrap4Inline Theme = `
 
 {{define "radio"}}
 <div class="form-group mr-3 {{if .GetErrors}}has-danger{{end}}">
-    <label class="mr-3 d-block">{{.Label}}</label>
+    <label class="mr-3 d-block">{{.RenderLabel}}</label>
     {{range $index, $option := .ValueOptions}}
     <div class="custom-control custom-control-inline custom-radio">
         <input type="radio" name="{{$.Name}}" value="{{$option.Value}}" id="{{$.Name}}{{$index}}" class="custom-control-input"
                {{if eq $.Value $option.Value}} checked {{else}} {{if $option.Selected}} checked {{end}} {{end}}
-               {{if $option.Disabled}} disabled{{end}} />
+               {{if $option.Disabled}} disabled{{end}}{{attributes $.GetConstraintAttributes}} />
         <label class="custom-control-label" for="{{$.Name}}{{$index}}">{{$option.Label}}</label>
     </div>
     {{end}}
@@ -681,8 +871,8 @@ var ThemeBootstrap4Inline Theme = `
     {{if .GetErrors}}
     <div class="form-control-feedback d-block w-100">
         <ul>
-            {{range .GetErrors}}
-            <li>{{.Message}}</li>
+            {{range .GetErrorMessages}}
+            <li>{{.}}</li>
             {{end}}
         </ul>
     </div>
@@ -692,7 +882,7 @@ var ThemeBootstrap4Inline Theme = `
 
 {{define "multicheckbox"}}
 <div class="form-group mr-3 {{if .GetErrors}}has-danger{{end}}">
-    <label class="mr-3 d-block">{{.Label}}</label>
+    <label class="mr-3 d-block">{{.RenderLabel}}</label>
     {{range $index, $option := .ValueOptions}}
     <div class="custom-control custom-control-inline custom-checkbox">
         <input type="checkbox" name="{{$.Name}}[]" value="{{$option.Value}}" id="{{$.Name}}{{$index}}" class="custom-control-input"
@@ -709,8 +899,8 @@ var ThemeBootstrap4Inline Theme = `
     {{if .GetErrors}}
     <div class="form-control-feedback d-block w-100">
         <ul>
-            {{range .GetErrors}}
-            <li>{{.Message}}</li>
+            {{range .GetErrorMessages}}
+            <li>{{.}}</li>
             {{end}}
         </ul>
     </div>
@@ -724,14 +914,14 @@ var ThemeBootstrap4Inline Theme = `
     <div class="custom-control custom-control-inline custom-checkbox" {{if .GetErrors}}has-danger{{end}}>
         <input type="checkbox" name="{{.Name}}" value="true" id="{{.Name}}" class="custom-control-input"
                {{if .IsChecked}} checked{{end}}/>
-        <label class="custom-control-label" for="{{.Name}}">{{.Label}}</label>
+        <label class="custom-control-label" for="{{.Name}}">{{.RenderLabel}}</label>
     </div>
 
     {{if .GetErrors}}
     <div class="form-control-feedback d-block w-100">
         <ul>
-            {{range .GetErrors}}
-            <li>{{.Message}}</li>
+            {{range .GetErrorMessages}}
+            <li>{{.}}</li>
             {{end}}
         </ul>
     </div>
@@ -741,39 +931,74 @@ var ThemeBootstrap4Inline Theme = `
 
 {{define "button"}}
 <div class="form-group mr-3">
-    <button type="submit" class="btn btn-primary ml-3"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}} style="min-width: 200px;">{{.Label}}</button>
+    <button type="submit" class="btn btn-primary ml-3"{{attributes .Attributes}} style="min-width: 200px;">{{.RenderLabel}}</button>
 </div>
 {{end}}
 
 {{define "submit"}}
 <div class="form-group mr-3">
-    <input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="submit" class="btn btn-primary ml-3"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}} value="{{.Label}}" style="min-width: 200px;">
+    <input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="submit" class="btn btn-primary ml-3"{{attributes .Attributes}} value="{{.Label}}" style="min-width: 200px;">
 </div>
 {{end}}
 
 {{define "image"}}
 <div class="form-group mr-3">
-    <input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="image" {{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}>
+    <input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="image" {{attributes .Attributes}}>
 </div>
 {{end}}
 
+{{define "captcha"}}
+{{$challenge := .GetChallenge}}
+<div class="form-group mr-3 {{if .GetErrors}}has-danger has-feedback{{end}}">
+    <label for="id_{{.Name}}" class="mr-3">{{.RenderLabel}}</label>
+    {{$challenge.Widget}}
+    {{range $challenge.Hidden}}<input type="hidden" name="{{.Key}}" value="{{.Value}}" />{{end}}
+    {{if $challenge.Answer}}
+    <input name="{{.Name}}" type="text" value="" autocomplete="off" {{attributes .Attributes .GetConstraintAttributes}}
+           {{if not (.HasAttribute "class")}}class="form-control"{{end}}
+    id="id_{{.Name}}" />
+    {{end}}
+
+    {{if .GetErrors}}
+    <div class="form-control-feedback d-block w-100">
+        <ul>
+            {{range .GetErrorMessages}}
+            <li>{{.}}</li>
+            {{end}}
+        </ul>
+    </div>
+    {{end}}
+</div>
+{{end}}
 `
 
 // https://v4-alpha.getbootstrap.com/components/forms/
 var ThemeBootstrap4Textual Theme = `
+{{define "formerrors"}}
+{{if .GetFormErrors}}
+<div class="alert alert-danger" role="alert">
+    <ul class="mb-0">
+        {{range .GetFormErrorMessages}}
+        <li>{{.}}</li>
+        {{end}}
+    </ul>
+</div>
+{{end}}
+{{end}}
+
 {{define "text"}}
 <div class="form-group row {{if .GetErrors}}has-danger{{end}}">
-    <label for="id_{{.Name}}" class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.Label}}</label>
+    <label for="id_{{.Name}}" class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.RenderLabel}}</label>
     <div class="col-xl-10 col-lg-9 col-md-12">
-    <input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}" {{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}
+    <input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}" {{attributes .Attributes .GetConstraintAttributes}}
     {{if not (.HasAttribute "class")}}class="form-control"{{end}}
     id="id_{{.Name}}" />
 
     {{if .GetErrors}}
     <div class="form-control-feedback">
     <ul>
-    {{range .GetErrors}}
-    <li>{{.Message}}</li>
+    {{range .GetErrorMessages}}
+    <li>{{.}}</li>
     {{end}}
     </ul>
     </div>
@@ -785,7 +1010,7 @@ var ThemeBootstrap4Textual Theme = `
 
 {{define "file"}}
 <div class="form-group row {{if .GetErrors}}has-danger{{end}}">
-    <label for="id_{{.Name}}" class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.Label}}</label>
+    <label for="id_{{.Name}}" class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.RenderLabel}}</label>
     <div class="col-xl-10 col-lg-9 col-md-12">
     {{if .GetFile}}
     <a href="{{.GetFile.Location}}" target="_blank">{{.GetFile.Name}}</a>
@@ -796,7 +1021,7 @@ var ThemeBootstrap4Textual Theme = `
     {{end}}
     {{end}}
     <label class="custom-file w-100">
-  	<input id="id_{{.Name}}" name="{{.Name}}" type="{{.Type}}" value="{{.Value}}" {{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}
+  	<input id="id_{{.Name}}" name="{{.Name}}" type="file"{{if .IsMultiple}} multiple{{end}} value="{{.Value}}" {{attributes .Attributes .GetConstraintAttributes}}
   	{{if not (.HasAttribute "class")}}class="custom-file-input"{{end}}>
         <span class="custom-file-control"></span>
     </label>
@@ -805,22 +1030,23 @@ var ThemeBootstrap4Textual Theme = `
 {{end}}
 
 {{define "hidden"}}
-<input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}} id="id_{{.Name}}" />
+<input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}"{{attributes .Attributes}} id="id_{{.Name}}" />
 {{end}}
 
 {{define "textarea"}}
 <div class="form-group row {{if .GetErrors}}has-danger{{end}}">
-    <label for="id_{{.Name}}" class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.Label}}</label>
+    <label for="id_{{.Name}}" class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.RenderLabel}}</label>
     <div class="col-xl-10 col-lg-9 col-md-12">
-    <textarea name="{{.Name}}"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}
+    <textarea name="{{.Name}}"{{attributes .Attributes .GetConstraintAttributes}}
     {{if not (.HasAttribute "class")}}class="form-control"{{end}}
-    id="id_{{.Name}}">{{.Value}}</textarea>
+    id="id_{{.Name}}">
+{{.Value}}</textarea>
 
     {{if .GetErrors}}
     <div class="form-control-feedback">
     <ul>
-    {{range .GetErrors}}
-    <li>{{.Message}}</li>
+    {{range .GetErrorMessages}}
+    <li>{{.}}</li>
     {{end}}
     </ul>
     </div>
@@ -832,9 +1058,9 @@ var ThemeBootstrap4Textual Theme = `
 
 {{define "select"}}
 <div class="form-group row {{if .GetErrors}}has-danger{{end}}">
-    <label for="id_{{.Name}}" class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.Label}}</label>
+    <label for="id_{{.Name}}" class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.RenderLabel}}</label>
     <div class="col-xl-10 col-lg-9 col-md-12">
-    <select name="{{.Name}}"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}
+    <select name="{{.Name}}"{{if .IsMultiple}} multiple{{end}}{{attributes .Attributes .GetConstraintAttributes}}
     {{if not (.HasAttribute "class")}}class="form-control"{{end}}
     id="id_{{.Name}}">
       {{range .ValueOptions}}
@@ -845,8 +1071,8 @@ var ThemeBootstrap4Textual Theme = `
     {{if .GetErrors}}
     <div class="form-control-feedback">
     <ul>
-    {{range .GetErrors}}
-    <li>{{.Message}}</li>
+    {{range .GetErrorMessages}}
+    <li>{{.}}</li>
     {{end}}
     </ul>
     </div>
@@ -858,13 +1084,13 @@ var ThemeBootstrap4Textual Theme = `
 
 {{define "radio"}}
 <div class="form-group row {{if .GetErrors}}has-danger{{end}}">
-    <label class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.Label}}</label>
+    <label class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.RenderLabel}}</label>
     <div class="col-xl-10 col-lg-9 col-md-12">
 	{{range $index, $option := .ValueOptions}}
 	  <div class="custom-control custom-control-inline custom-radio">
 	    <input type="radio" name="{{$.Name}}" value="{{$option.Value}}" id="{{$.Name}}{{$index}}" class="custom-control-input"
 		     {{if eq $.Value $option.Value}} checked {{else}} {{if $option.Selected}} checked {{end}} {{end}}
-		     {{if $option.Disabled}} disabled{{end}}{{range $.Attributes}} {{.Key}}="{{.Value}}"{{end}} />
+		     {{if $option.Disabled}} disabled{{end}}{{attributes $.Attributes $.GetConstraintAttributes}} />
 	    <label class="custom-control-label" for="{{$.Name}}{{$index}}">{{$option.Label}}</label>
 	  </div>
 	{{end}}
@@ -872,8 +1098,8 @@ var ThemeBootstrap4Textual Theme = `
 	    {{if .GetErrors}}
 	    <div class="form-control-feedback">
 	    <ul>
-	    {{range .GetErrors}}
-	    <li>{{.Message}}</li>
+	    {{range .GetErrorMessages}}
+	    <li>{{.}}</li>
 	    {{end}}
 	    </ul>
 	    </div>
@@ -884,7 +1110,7 @@ var ThemeBootstrap4Textual Theme = `
 
 {{define "multicheckbox"}}
 <div class="form-group row {{if .GetErrors}}has-danger{{end}}">
-    <label class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.Label}}</label>
+    <label class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.RenderLabel}}</label>
     <div class="col-xl-10 col-lg-9 col-md-12">
     {{range $index, $option := .ValueOptions}}
     <div class="custom-control custom-control-inline custom-checkbox ml-1">
@@ -902,8 +1128,8 @@ var ThemeBootstrap4Textual Theme = `
     {{if .GetErrors}}
     <div class="form-control-feedback">
     <ul>
-    {{range .GetErrors}}
-    <li>{{.Message}}</li>
+    {{range .GetErrorMessages}}
+    <li>{{.}}</li>
     {{end}}
     </ul>
     </div>
@@ -919,14 +1145,14 @@ var ThemeBootstrap4Textual Theme = `
   <div class="custom-control custom-control-inline custom-checkbox ml-3">
     <input type="checkbox" name="{{.Name}}" value="true" id="{{.Name}}" class="custom-control-input"
      {{if .IsChecked}} checked{{end}}/>
-    <label class="custom-control-label" for="{{.Name}}">{{.Label}}</label>
+    <label class="custom-control-label" for="{{.Name}}">{{.RenderLabel}}</label>
   </div>
 
     {{if .GetErrors}}
     <div class="form-control-feedback">
     <ul>
-    {{range .GetErrors}}
-    <li>{{.Message}}</li>
+    {{range .GetErrorMessages}}
+    <li>{{.}}</li>
     {{end}}
     </ul>
     </div>
@@ -938,7 +1164,7 @@ var ThemeBootstrap4Textual Theme = `
 {{define "button"}}
 <div class="form-group row">
 <div class="offset-xl-2 offset-lg-3">
-<button type="submit" class="btn btn-primary ml-3"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}} style="min-width: 200px;">{{.Label}}</button>
+<button type="submit" class="btn btn-primary ml-3"{{attributes .Attributes}} style="min-width: 200px;">{{.RenderLabel}}</button>
 </div>
 </div>
 {{end}}
@@ -946,7 +1172,7 @@ var ThemeBootstrap4Textual Theme = `
 {{define "submit"}}
 <div class="form-group row">
 <div class="offset-xl-2 offset-lg-3">
-<input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="submit" class="btn btn-primary ml-3"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}} value="{{.Label}}" style="min-width: 200px;">
+<input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="submit" class="btn btn-primary ml-3"{{attributes .Attributes}} value="{{.Label}}" style="min-width: 200px;">
 </div>
 </div>
 {{end}}
@@ -954,26 +1180,64 @@ var ThemeBootstrap4Textual Theme = `
 {{define "image"}}
 <div class="form-group row">
 <div class="offset-xl-2 offset-lg-3">
-<input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="image" {{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}>
+<input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="image" {{attributes .Attributes}}>
 </div>
 </div>
 {{end}}
+{{define "captcha"}}
+{{$challenge := .GetChallenge}}
+<div class="form-group row {{if .GetErrors}}has-danger{{end}}">
+    <label for="id_{{.Name}}" class="col-xl-2 col-lg-3 col-md-12 col-form-label">{{.RenderLabel}}</label>
+    <div class="col-xl-10 col-lg-9 col-md-12">
+    {{$challenge.Widget}}
+    {{range $challenge.Hidden}}<input type="hidden" name="{{.Key}}" value="{{.Value}}" />{{end}}
+    {{if $challenge.Answer}}
+    <input name="{{.Name}}" type="text" value="" autocomplete="off" {{attributes .Attributes .GetConstraintAttributes}}
+    {{if not (.HasAttribute "class")}}class="form-control"{{end}}
+    id="id_{{.Name}}" />
+    {{end}}
+
+    {{if .GetErrors}}
+    <div class="form-control-feedback">
+    <ul>
+    {{range .GetErrorMessages}}
+    <li>{{.}}</li>
+    {{end}}
+    </ul>
+    </div>
+    {{end}}
+
+    </div>
+</div>
+{{end}}
 `
 
 // https://v4-alpha.getbootstrap.com/components/forms/
 var ThemeBootstrap4 Theme = `
+{{define "formerrors"}}
+{{if .GetFormErrors}}
+<div class="alert alert-danger" role="alert">
+    <ul class="mb-0">
+        {{range .GetFormErrorMessages}}
+        <li>{{.}}</li>
+        {{end}}
+    </ul>
+</div>
+{{end}}
+{{end}}
+
 {{define "text"}}
 <div class="form-group{{if .GetErrors}} has-danger{{end}}">
-    <label for="id_{{.Name}}">{{.Label}}</label>
-    <input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}" {{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}
+    <label for="id_{{.Name}}">{{.RenderLabel}}</label>
+    <input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}" {{attributes .Attributes .GetConstraintAttributes}}
     {{if not (.HasAttribute "class")}}class="form-control"{{end}}
     id="id_{{.Name}}" />
 
     {{if .GetErrors}}
     <div class="form-control-feedback">
     <ul>
-    {{range .GetErrors}}
-    <li>{{.Message}}</li>
+    {{range .GetErrorMessages}}
+    <li>{{.}}</li>
     {{end}}
     </ul>
     </div>
@@ -983,7 +1247,7 @@ var ThemeBootstrap4 Theme = `
 
 {{define "file"}}
 <div class="form-group{{if .GetErrors}} has-danger{{end}}">
-    <label for="id_{{.Name}}">{{.Label}}</label>
+    <label for="id_{{.Name}}">{{.RenderLabel}}</label>
     <label class="custom-file w-100">
 
     {{if .GetFile}}
@@ -994,7 +1258,7 @@ var ThemeBootstrap4 Theme = `
     </a>
     {{end}}
     {{end}}
-  	<input id="id_{{.Name}}" name="{{.Name}}" type="{{.Type}}" value="{{.Value}}" {{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}
+  	<input id="id_{{.Name}}" name="{{.Name}}" type="file"{{if .IsMultiple}} multiple{{end}} value="{{.Value}}" {{attributes .Attributes .GetConstraintAttributes}}
   	{{if not (.HasAttribute "class")}}class="custom-file-input"{{end}}>
         <span class="custom-file-control"></span>
     </label>
@@ -1002,21 +1266,22 @@ var ThemeBootstrap4 Theme = `
 {{end}}
 
 {{define "hidden"}}
-<input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}} id="id_{{.Name}}" />
+<input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}"{{attributes .Attributes}} id="id_{{.Name}}" />
 {{end}}
 
 {{define "textarea"}}
 <div class="form-group{{if .GetErrors}} has-danger{{end}}">
-    <label for="id_{{.Name}}">{{.Label}}</label>
-    <textarea name="{{.Name}}"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}
+    <label for="id_{{.Name}}">{{.RenderLabel}}</label>
+    <textarea name="{{.Name}}"{{attributes .Attributes .GetConstraintAttributes}}
     {{if not (.HasAttribute "class")}}class="form-control"{{end}}
-    id="id_{{.Name}}">{{.Value}}</textarea>
+    id="id_{{.Name}}">
+{{.Value}}</textarea>
 
     {{if .GetErrors}}
     <div class="form-control-feedback">
     <ul>
-    {{range .GetErrors}}
-    <li>{{.Message}}</li>
+    {{range .GetErrorMessages}}
+    <li>{{.}}</li>
     {{end}}
     </ul>
     </div>
@@ -1027,8 +1292,8 @@ var ThemeBootstrap4 Theme = `
 
 {{define "select"}}
 <div class="form-group{{if .GetErrors}} has-danger{{end}}">
-    <label for="id_{{.Name}}">{{.Label}}</label>
-    <select name="{{.Name}}"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}
+    <label for="id_{{.Name}}">{{.RenderLabel}}</label>
+    <select name="{{.Name}}"{{if .IsMultiple}} multiple{{end}}{{attributes .Attributes .GetConstraintAttributes}}
     {{if not (.HasAttribute "class")}}class="form-control"{{end}}
     id="id_{{.Name}}">
       {{range .ValueOptions}}
@@ -1039,8 +1304,8 @@ var ThemeBootstrap4 Theme = `
     {{if .GetErrors}}
     <div class="form-control-feedback">
     <ul>
-    {{range .GetErrors}}
-    <li>{{.Message}}</li>
+    {{range .GetErrorMessages}}
+    <li>{{.}}</li>
     {{end}}
     </ul>
     </div>
@@ -1051,12 +1316,12 @@ var ThemeBootstrap4 Theme = `
 
 {{define "radio"}}
 <div class="form-group{{if .GetErrors}} has-danger{{end}}">
-    <label>{{.Label}}</label>
+    <label>{{.RenderLabel}}</label>
 	{{range $index, $option := .ValueOptions}}
 	  <div class="custom-control custom-control-inline custom-radio">
 	    <input type="radio" name="{{$.Name}}" value="{{$option.Value}}" id="{{$.Name}}{{$index}}" class="custom-control-input"
 		     {{if eq $.Value $option.Value}} checked {{else}} {{if $option.Selected}} checked {{end}} {{end}}
-		     {{if $option.Disabled}} disabled{{end}} />
+		     {{if $option.Disabled}} disabled{{end}}{{attributes $.GetConstraintAttributes}} />
 	    <label class="custom-control-label" for="{{$.Name}}{{$index}}">{{$option.Label}}</label>
 	  </div>
 	{{end}}
@@ -1064,8 +1329,8 @@ var ThemeBootstrap4 Theme = `
 	    {{if .GetErrors}}
 	    <div class="form-control-feedback">
 	    <ul>
-	    {{range .GetErrors}}
-	    <li>{{.Message}}</li>
+	    {{range .GetErrorMessages}}
+	    <li>{{.}}</li>
 	    {{end}}
 	    </ul>
 	    </div>
@@ -1075,7 +1340,7 @@ var ThemeBootstrap4 Theme = `
 
 {{define "multicheckbox"}}
 <div class="form-group{{if .GetErrors}} has-danger{{end}}">
-    <label>{{.Label}}</label>
+    <label>{{.RenderLabel}}</label>
     {{range $index, $option := .ValueOptions}}
     <div class="custom-control custom-control-inline custom-checkbox">
       <input type="checkbox" name="{{$.Name}}[]" value="{{$option.Value}}" id="{{$.Name}}{{$index}}" class="custom-control-input"
@@ -1092,8 +1357,8 @@ var ThemeBootstrap4 Theme = `
     {{if .GetErrors}}
     <div class="form-control-feedback">
     <ul>
-    {{range .GetErrors}}
-    <li>{{.Message}}</li>
+    {{range .GetErrorMessages}}
+    <li>{{.}}</li>
     {{end}}
     </ul>
     </div>
@@ -1107,14 +1372,14 @@ var ThemeBootstrap4 Theme = `
   <div class="custom-control custom-control-inline custom-checkbox mr-3">
     <input type="checkbox" name="{{.Name}}" value="true" id="{{.Name}}" class="custom-control-input"
      {{if .IsChecked}} checked{{end}}/>
-    <label class="custom-control-label" for="{{.Name}}">{{.Label}}</label>
+    <label class="custom-control-label" for="{{.Name}}">{{.RenderLabel}}</label>
   </div>
 
     {{if .GetErrors}}
     <div class="form-control-feedback">
     <ul>
-    {{range .GetErrors}}
-    <li>{{.Message}}</li>
+    {{range .GetErrorMessages}}
+    <li>{{.}}</li>
     {{end}}
     </ul>
     </div>
@@ -1124,39 +1389,1134 @@ var ThemeBootstrap4 Theme = `
 
 {{define "button"}}
 <div class="form-group">
-<button type="submit" class="btn btn-primary ml-3"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}} style="min-width: 200px;">{{.Label}}</button>
+<button type="submit" class="btn btn-primary ml-3"{{attributes .Attributes}} style="min-width: 200px;">{{.RenderLabel}}</button>
 </div>
 {{end}}
 
 {{define "submit"}}
 <div class="form-group">
-<input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="submit" class="btn btn-primary ml-3"{{range .Attributes}} {{.Key}}="{{.Value}}"{{end}} value="{{.Label}}" style="min-width: 200px;">
+<input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="submit" class="btn btn-primary ml-3"{{attributes .Attributes}} value="{{.Label}}" style="min-width: 200px;">
 </div>
 {{end}}
 
 {{define "image"}}
 <div class="form-group">
-<input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="image" {{range .Attributes}} {{.Key}}="{{.Value}}"{{end}}>
+<input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="image" {{attributes .Attributes}}>
+</div>
+{{end}}
+{{define "captcha"}}
+{{$challenge := .GetChallenge}}
+<div class="form-group{{if .GetErrors}} has-danger{{end}}">
+    <label for="id_{{.Name}}">{{.RenderLabel}}</label>
+    {{$challenge.Widget}}
+    {{range $challenge.Hidden}}<input type="hidden" name="{{.Key}}" value="{{.Value}}" />{{end}}
+    {{if $challenge.Answer}}
+    <input name="{{.Name}}" type="text" value="" autocomplete="off" {{attributes .Attributes .GetConstraintAttributes}}
+    {{if not (.HasAttribute "class")}}class="form-control"{{end}}
+    id="id_{{.Name}}" />
+    {{end}}
+
+    {{if .GetErrors}}
+    <div class="form-control-feedback">
+    <ul>
+    {{range .GetErrorMessages}}
+    <li>{{.}}</li>
+    {{end}}
+    </ul>
+    </div>
+    {{end}}
+</div>
+{{end}}
+`
+
+// https://getbootstrap.com/docs/5.3/forms/overview/
+// Checkboxes with a role attribute, e.g. role="switch", render as switches.
+var ThemeBootstrap5 Theme = `
+{{define "formerrors"}}
+{{if .GetFormErrors}}
+<div class="alert alert-danger" role="alert">
+    <ul class="mb-0">
+        {{range .GetFormErrorMessages}}
+        <li>{{.}}</li>
+        {{end}}
+    </ul>
+</div>
+{{end}}
+{{end}}
+
+{{define "errors"}}
+{{if .GetErrors}}
+<div id="id_{{.Name}}_feedback" class="invalid-feedback d-block">
+    {{range .GetErrorMessages}}
+    <div>{{.}}</div>
+    {{end}}
+</div>
+{{end}}
+{{end}}
+
+{{define "label"}}
+<label for="id_{{.Name}}" class="form-label">{{.RenderLabel}}</label>
+{{end}}
+
+{{define "text"}}
+<div class="mb-3">
+    {{template "label" .}}
+    <input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}"{{attributes .Attributes .GetConstraintAttributes}}
+    {{if not (.HasAttribute "class")}}class="form-control{{if .GetErrors}} is-invalid{{end}}"{{end}}
+    id="id_{{.Name}}"{{if .GetErrors}} aria-describedby="id_{{.Name}}_feedback"{{end}} />
+    {{template "errors" .}}
+</div>
+{{end}}
+
+{{define "file"}}
+<div class="mb-3">
+    {{template "label" .}}
+    {{if .GetFile}}
+    <div class="form-text mb-1">
+        <a href="{{.GetFile.Location}}" target="_blank">{{.GetFile.Name}}</a>
+        {{if ne .GetDeletionUrl ""}}
+        <a class="text-danger btn-confirm-delete" href="{{.GetDeletionUrl}}"><i class="fa fa-times"></i></a>
+        {{end}}
+    </div>
+    {{end}}
+    <input name="{{.Name}}" type="file"{{if .IsMultiple}} multiple{{end}}{{attributes .Attributes .GetConstraintAttributes}}
+    {{if not (.HasAttribute "class")}}class="form-control{{if .GetErrors}} is-invalid{{end}}"{{end}}
+    id="id_{{.Name}}"{{if .GetErrors}} aria-describedby="id_{{.Name}}_feedback"{{end}} />
+    {{template "errors" .}}
+</div>
+{{end}}
+
+{{define "hidden"}}
+<input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}"{{attributes .Attributes}} id="id_{{.Name}}" />
+{{end}}
+
+{{define "textarea"}}
+<div class="mb-3">
+    {{template "label" .}}
+    <textarea name="{{.Name}}"{{attributes .Attributes .GetConstraintAttributes}}
+    {{if not (.HasAttribute "class")}}class="form-control{{if .GetErrors}} is-invalid{{end}}"{{end}}
+    id="id_{{.Name}}"{{if .GetErrors}} aria-describedby="id_{{.Name}}_feedback"{{end}}>
+{{.Value}}</textarea>
+    {{template "errors" .}}
+</div>
+{{end}}
+
+{{define "select"}}
+<div class="mb-3">
+    {{template "label" .}}
+    <select name="{{.Name}}"{{if .IsMultiple}} multiple{{end}}{{attributes .Attributes .GetConstraintAttributes}}
+    {{if not (.HasAttribute "class")}}class="form-select{{if .GetErrors}} is-invalid{{end}}"{{end}}
+    id="id_{{.Name}}"{{if .GetErrors}} aria-describedby="id_{{.Name}}_feedback"{{end}}>
+        {{range .ValueOptions}}
+        <option value="{{.Value}}"{{if .Selected}} selected{{end}}{{if .Disabled}} disabled{{end}}>{{.Label}}</option>
+        {{end}}
+    </select>
+    {{template "errors" .}}
+</div>
+{{end}}
+
+{{define "radio"}}
+<fieldset class="mb-3">
+    <legend class="form-label fs-6">{{.RenderLabel}}</legend>
+    {{range $index, $option := .ValueOptions}}
+    <div class="form-check">
+        <input type="radio" name="{{$.Name}}" value="{{$option.Value}}" id="id_{{$.Name}}_{{$index}}"
+               class="form-check-input{{if $.GetErrors}} is-invalid{{end}}"
+               {{if eq $.Value $option.Value}} checked{{else}}{{if $option.Selected}} checked{{end}}{{end}}
+               {{if $option.Disabled}} disabled{{end}}{{attributes $.Attributes $.GetConstraintAttributes}}{{if $.GetErrors}} aria-describedby="id_{{$.Name}}_feedback"{{end}} />
+        <label class="form-check-label" for="id_{{$.Name}}_{{$index}}">{{$option.Label}}</label>
+    </div>
+    {{end}}
+    {{template "errors" .}}
+</fieldset>
+{{end}}
+
+{{define "multicheckbox"}}
+<fieldset class="mb-3">
+    <legend class="form-label fs-6">{{.RenderLabel}}</legend>
+    {{range $index, $option := .ValueOptions}}
+    <div class="form-check">
+        <input type="checkbox" name="{{$.Name}}[]" value="{{$option.Value}}" id="id_{{$.Name}}_{{$index}}"
+               class="form-check-input{{if $.GetErrors}} is-invalid{{end}}"
+               {{if $.IsCheckedInValues $option.Value}} checked{{else}}{{if $option.Selected}} checked{{end}}{{end}}
+               {{if $option.Disabled}} disabled{{end}}{{attributes $.Attributes}}{{if $.GetErrors}} aria-describedby="id_{{$.Name}}_feedback"{{end}} />
+        <label class="form-check-label" for="id_{{$.Name}}_{{$index}}">{{$option.Label}}</label>
+    </div>
+    {{end}}
+    {{template "errors" .}}
+</fieldset>
+{{end}}
+
+{{define "checkbox"}}
+<div class="mb-3">
+    <div class="form-check{{if .HasAttribute "role"}} form-switch{{end}}">
+        <input type="checkbox" name="{{.Name}}" value="true" id="id_{{.Name}}"{{attributes .Attributes}}
+               {{if not (.HasAttribute "class")}}class="form-check-input{{if .GetErrors}} is-invalid{{end}}"{{end}}
+               {{if .IsChecked}} checked{{end}}{{if .GetErrors}} aria-describedby="id_{{.Name}}_feedback"{{end}} />
+        <label class="form-check-label" for="id_{{.Name}}">{{.RenderLabel}}</label>
+    </div>
+    {{template "errors" .}}
+</div>
+{{end}}
+
+{{define "button"}}
+<div class="mb-3">
+    <button type="submit"{{if not (.HasAttribute "class")}} class="btn btn-primary"{{end}}{{attributes .Attributes}}>{{.RenderLabel}}</button>
+</div>
+{{end}}
+
+{{define "submit"}}
+<div class="mb-3">
+    <input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="submit"{{if not (.HasAttribute "class")}} class="btn btn-primary"{{end}}{{attributes .Attributes}} value="{{.Label}}">
+</div>
+{{end}}
+
+{{define "image"}}
+<div class="mb-3">
+    <input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="image"{{attributes .Attributes}}>
+</div>
+{{end}}
+{{define "captcha"}}
+{{$challenge := .GetChallenge}}
+<div class="mb-3">
+    {{template "label" .}}
+    {{$challenge.Widget}}
+    {{range $challenge.Hidden}}<input type="hidden" name="{{.Key}}" value="{{.Value}}" />{{end}}
+    {{if $challenge.Answer}}
+    <input name="{{.Name}}" type="text" value="" autocomplete="off"{{attributes .Attributes .GetConstraintAttributes}}
+    {{if not (.HasAttribute "class")}}class="form-control{{if .GetErrors}} is-invalid{{end}}"{{end}}
+    id="id_{{.Name}}"{{if .GetErrors}} aria-describedby="id_{{.Name}}_feedback"{{end}} />
+    {{end}}
+    {{template "errors" .}}
+</div>
+{{end}}
+`
+
+// https://getbootstrap.com/docs/5.3/forms/layout/#inline-forms
+var ThemeBootstrap5Inline Theme = `
+{{define "form"}}
+<form action="{{.GetAction}}" method="{{.GetMethod}}"{{if .GetId}} id="{{.GetId}}"{{end}}{{if and .IsMultipart (not (.HasAttribute "enctype"))}} enctype="multipart/form-data"{{end}}{{if .IsNoValidate}} novalidate{{end}}{{attributes .GetAttributes}}{{if not (.HasAttribute "class")}} class="row row-cols-lg-auto g-3 align-items-center"{{end}}>
+{{template "formerrors" .}}
+{{.RenderElements}}
+</form>
+{{end}}
+
+{{define "formerrors"}}
+{{if .GetFormErrors}}
+<div class="alert alert-danger" role="alert">
+    <ul class="mb-0">
+        {{range .GetFormErrorMessages}}
+        <li>{{.}}</li>
+        {{end}}
+    </ul>
+</div>
+{{end}}
+{{end}}
+
+{{define "errors"}}
+{{if .GetErrors}}
+<div id="id_{{.Name}}_feedback" class="invalid-feedback d-block">
+    {{range .GetErrorMessages}}
+    <div>{{.}}</div>
+    {{end}}
+</div>
+{{end}}
+{{end}}
+
+{{define "label"}}
+<label for="id_{{.Name}}" class="visually-hidden">{{.RenderLabel}}</label>
+{{end}}
+
+{{define "text"}}
+<div class="col-12">
+    {{template "label" .}}
+    <input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}"{{attributes .Attributes .GetConstraintAttributes}}{{if not (.HasAttribute "placeholder")}} placeholder="{{.Label}}"{{end}}
+    {{if not (.HasAttribute "class")}}class="form-control{{if .GetErrors}} is-invalid{{end}}"{{end}}
+    id="id_{{.Name}}"{{if .GetErrors}} aria-describedby="id_{{.Name}}_feedback"{{end}} />
+    {{template "errors" .}}
+</div>
+{{end}}
+
+{{define "file"}}
+<div class="col-12">
+    {{template "label" .}}
+    {{if .GetFile}}
+    <div class="form-text mb-1">
+        <a href="{{.GetFile.Location}}" target="_blank">{{.GetFile.Name}}</a>
+        {{if ne .GetDeletionUrl ""}}
+        <a class="text-danger btn-confirm-delete" href="{{.GetDeletionUrl}}"><i class="fa fa-times"></i></a>
+        {{end}}
+    </div>
+    {{end}}
+    <input name="{{.Name}}" type="file"{{if .IsMultiple}} multiple{{end}}{{attributes .Attributes .GetConstraintAttributes}}
+    {{if not (.HasAttribute "class")}}class="form-control{{if .GetErrors}} is-invalid{{end}}"{{end}}
+    id="id_{{.Name}}"{{if .GetErrors}} aria-describedby="id_{{.Name}}_feedback"{{end}} />
+    {{template "errors" .}}
+</div>
+{{end}}
+
+{{define "hidden"}}
+<input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}"{{attributes .Attributes}} id="id_{{.Name}}" />
+{{end}}
+
+{{define "textarea"}}
+<div class="col-12">
+    {{template "label" .}}
+    <textarea name="{{.Name}}"{{attributes .Attributes .GetConstraintAttributes}}{{if not (.HasAttribute "placeholder")}} placeholder="{{.Label}}"{{end}}
+    {{if not (.HasAttribute "class")}}class="form-control{{if .GetErrors}} is-invalid{{end}}"{{end}}
+    id="id_{{.Name}}"{{if .GetErrors}} aria-describedby="id_{{.Name}}_feedback"{{end}}>
+{{.Value}}</textarea>
+    {{template "errors" .}}
+</div>
+{{end}}
+
+{{define "select"}}
+<div class="col-12">
+    {{template "label" .}}
+    <select name="{{.Name}}"{{if .IsMultiple}} multiple{{end}}{{attributes .Attributes .GetConstraintAttributes}}
+    {{if not (.HasAttribute "class")}}class="form-select{{if .GetErrors}} is-invalid{{end}}"{{end}}
+    id="id_{{.Name}}"{{if .GetErrors}} aria-describedby="id_{{.Name}}_feedback"{{end}}>
+        {{range .ValueOptions}}
+        <option value="{{.Value}}"{{if .Selected}} selected{{end}}{{if .Disabled}} disabled{{end}}>{{.Label}}</option>
+        {{end}}
+    </select>
+    {{template "errors" .}}
+</div>
+{{end}}
+
+{{define "radio"}}
+<fieldset class="col-12">
+    <legend class="visually-hidden">{{.RenderLabel}}</legend>
+    {{range $index, $option := .ValueOptions}}
+    <div class="form-check form-check-inline">
+        <input type="radio" name="{{$.Name}}" value="{{$option.Value}}" id="id_{{$.Name}}_{{$index}}"
+               class="form-check-input{{if $.GetErrors}} is-invalid{{end}}"
+               {{if eq $.Value $option.Value}} checked{{else}}{{if $option.Selected}} checked{{end}}{{end}}
+               {{if $option.Disabled}} disabled{{end}}{{attributes $.Attributes $.GetConstraintAttributes}}{{if $.GetErrors}} aria-describedby="id_{{$.Name}}_feedback"{{end}} />
+        <label class="form-check-label" for="id_{{$.Name}}_{{$index}}">{{$option.Label}}</label>
+    </div>
+    {{end}}
+    {{template "errors" .}}
+</fieldset>
+{{end}}
+
+{{define "multicheckbox"}}
+<fieldset class="col-12">
+    <legend class="visually-hidden">{{.RenderLabel}}</legend>
+    {{range $index, $option := .ValueOptions}}
+    <div class="form-check form-check-inline">
+        <input type="checkbox" name="{{$.Name}}[]" value="{{$option.Value}}" id="id_{{$.Name}}_{{$index}}"
+               class="form-check-input{{if $.GetErrors}} is-invalid{{end}}"
+               {{if $.IsCheckedInValues $option.Value}} checked{{else}}{{if $option.Selected}} checked{{end}}{{end}}
+               {{if $option.Disabled}} disabled{{end}}{{attributes $.Attributes}}{{if $.GetErrors}} aria-describedby="id_{{$.Name}}_feedback"{{end}} />
+        <label class="form-check-label" for="id_{{$.Name}}_{{$index}}">{{$option.Label}}</label>
+    </div>
+    {{end}}
+    {{template "errors" .}}
+</fieldset>
+{{end}}
+
+{{define "checkbox"}}
+<div class="col-12">
+    <div class="form-check{{if .HasAttribute "role"}} form-switch{{end}}">
+        <input type="checkbox" name="{{.Name}}" value="true" id="id_{{.Name}}"{{attributes .Attributes}}
+               {{if not (.HasAttribute "class")}}class="form-check-input{{if .GetErrors}} is-invalid{{end}}"{{end}}
+               {{if .IsChecked}} checked{{end}}{{if .GetErrors}} aria-describedby="id_{{.Name}}_feedback"{{end}} />
+        <label class="form-check-label" for="id_{{.Name}}">{{.RenderLabel}}</label>
+    </div>
+    {{template "errors" .}}
+</div>
+{{end}}
+
+{{define "button"}}
+<div class="col-12">
+    <button type="submit"{{if not (.HasAttribute "class")}} class="btn btn-primary"{{end}}{{attributes .Attributes}}>{{.RenderLabel}}</button>
+</div>
+{{end}}
+
+{{define "submit"}}
+<div class="col-12">
+    <input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="submit"{{if not (.HasAttribute "class")}} class="btn btn-primary"{{end}}{{attributes .Attributes}} value="{{.Label}}">
+</div>
+{{end}}
+
+{{define "image"}}
+<div class="col-12">
+    <input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="image"{{attributes .Attributes}}>
+</div>
+{{end}}
+{{define "captcha"}}
+{{$challenge := .GetChallenge}}
+<div class="col-12">
+    {{template "label" .}}
+    {{$challenge.Widget}}
+    {{range $challenge.Hidden}}<input type="hidden" name="{{.Key}}" value="{{.Value}}" />{{end}}
+    {{if $challenge.Answer}}
+    <input name="{{.Name}}" type="text" value="" autocomplete="off"{{attributes .Attributes .GetConstraintAttributes}}{{if not (.HasAttribute "placeholder")}} placeholder="{{.Label}}"{{end}}
+    {{if not (.HasAttribute "class")}}class="form-control{{if .GetErrors}} is-invalid{{end}}"{{end}}
+    id="id_{{.Name}}"{{if .GetErrors}} aria-describedby="id_{{.Name}}_feedback"{{end}} />
+    {{end}}
+    {{template "errors" .}}
 </div>
 {{end}}
 `
 
-func NewTemplate(theme Theme, funcMap template.FuncMap) *template.Template {
+// https://getbootstrap.com/docs/5.3/forms/layout/#horizontal-form
+var ThemeBootstrap5Horizontal Theme = `
+{{define "formerrors"}}
+{{if .GetFormErrors}}
+<div class="alert alert-danger" role="alert">
+    <ul class="mb-0">
+        {{range .GetFormErrorMessages}}
+        <li>{{.}}</li>
+        {{end}}
+    </ul>
+</div>
+{{end}}
+{{end}}
+
+{{define "errors"}}
+{{if .GetErrors}}
+<div id="id_{{.Name}}_feedback" class="invalid-feedback d-block">
+    {{range .GetErrorMessages}}
+    <div>{{.}}</div>
+    {{end}}
+</div>
+{{end}}
+{{end}}
+
+{{define "label"}}
+<label for="id_{{.Name}}" class="col-sm-2 col-form-label">{{.RenderLabel}}</label>
+{{end}}
+
+{{define "text"}}
+<div class="row mb-3">
+    {{template "label" .}}
+    <div class="col-sm-10">
+        <input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}"{{attributes .Attributes .GetConstraintAttributes}}
+        {{if not (.HasAttribute "class")}}class="form-control{{if .GetErrors}} is-invalid{{end}}"{{end}}
+        id="id_{{.Name}}"{{if .GetErrors}} aria-describedby="id_{{.Name}}_feedback"{{end}} />
+        {{template "errors" .}}
+    </div>
+</div>
+{{end}}
+
+{{define "file"}}
+<div class="row mb-3">
+    {{template "label" .}}
+    <div class="col-sm-10">
+        {{if .GetFile}}
+        <div class="form-text mb-1">
+            <a href="{{.GetFile.Location}}" target="_blank">{{.GetFile.Name}}</a>
+            {{if ne .GetDeletionUrl ""}}
+            <a class="text-danger btn-confirm-delete" href="{{.GetDeletionUrl}}"><i class="fa fa-times"></i></a>
+            {{end}}
+        </div>
+        {{end}}
+        <input name="{{.Name}}" type="file"{{if .IsMultiple}} multiple{{end}}{{attributes .Attributes .GetConstraintAttributes}}
+        {{if not (.HasAttribute "class")}}class="form-control{{if .GetErrors}} is-invalid{{end}}"{{end}}
+        id="id_{{.Name}}"{{if .GetErrors}} aria-describedby="id_{{.Name}}_feedback"{{end}} />
+        {{template "errors" .}}
+    </div>
+</div>
+{{end}}
+
+{{define "hidden"}}
+<input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}"{{attributes .Attributes}} id="id_{{.Name}}" />
+{{end}}
+
+{{define "textarea"}}
+<div class="row mb-3">
+    {{template "label" .}}
+    <div class="col-sm-10">
+        <textarea name="{{.Name}}"{{attributes .Attributes .GetConstraintAttributes}}
+        {{if not (.HasAttribute "class")}}class="form-control{{if .GetErrors}} is-invalid{{end}}"{{end}}
+        id="id_{{.Name}}"{{if .GetErrors}} aria-describedby="id_{{.Name}}_feedback"{{end}}>
+{{.Value}}</textarea>
+        {{template "errors" .}}
+    </div>
+</div>
+{{end}}
+
+{{define "select"}}
+<div class="row mb-3">
+    {{template "label" .}}
+    <div class="col-sm-10">
+        <select name="{{.Name}}"{{if .IsMultiple}} multiple{{end}}{{attributes .Attributes .GetConstraintAttributes}}
+        {{if not (.HasAttribute "class")}}class="form-select{{if .GetErrors}} is-invalid{{end}}"{{end}}
+        id="id_{{.Name}}"{{if .GetErrors}} aria-describedby="id_{{.Name}}_feedback"{{end}}>
+            {{range .ValueOptions}}
+            <option value="{{.Value}}"{{if .Selected}} selected{{end}}{{if .Disabled}} disabled{{end}}>{{.Label}}</option>
+            {{end}}
+        </select>
+        {{template "errors" .}}
+    </div>
+</div>
+{{end}}
+
+{{define "radio"}}
+<fieldset class="row mb-3">
+    <legend class="col-form-label col-sm-2 pt-0">{{.RenderLabel}}</legend>
+    <div class="col-sm-10">
+        {{range $index, $option := .ValueOptions}}
+        <div class="form-check">
+            <input type="radio" name="{{$.Name}}" value="{{$option.Value}}" id="id_{{$.Name}}_{{$index}}"
+                   class="form-check-input{{if $.GetErrors}} is-invalid{{end}}"
+                   {{if eq $.Value $option.Value}} checked{{else}}{{if $option.Selected}} checked{{end}}{{end}}
+                   {{if $option.Disabled}} disabled{{end}}{{attributes $.Attributes $.GetConstraintAttributes}}{{if $.GetErrors}} aria-describedby="id_{{$.Name}}_feedback"{{end}} />
+            <label class="form-check-label" for="id_{{$.Name}}_{{$index}}">{{$option.Label}}</label>
+        </div>
+        {{end}}
+        {{template "errors" .}}
+    </div>
+</fieldset>
+{{end}}
+
+{{define "multicheckbox"}}
+<fieldset class="row mb-3">
+    <legend class="col-form-label col-sm-2 pt-0">{{.RenderLabel}}</legend>
+    <div class="col-sm-10">
+        {{range $index, $option := .ValueOptions}}
+        <div class="form-check">
+            <input type="checkbox" name="{{$.Name}}[]" value="{{$option.Value}}" id="id_{{$.Name}}_{{$index}}"
+                   class="form-check-input{{if $.GetErrors}} is-invalid{{end}}"
+                   {{if $.IsCheckedInValues $option.Value}} checked{{else}}{{if $option.Selected}} checked{{end}}{{end}}
+                   {{if $option.Disabled}} disabled{{end}}{{attributes $.Attributes}}{{if $.GetErrors}} aria-describedby="id_{{$.Name}}_feedback"{{end}} />
+            <label class="form-check-label" for="id_{{$.Name}}_{{$index}}">{{$option.Label}}</label>
+        </div>
+        {{end}}
+        {{template "errors" .}}
+    </div>
+</fieldset>
+{{end}}
+
+{{define "checkbox"}}
+<div class="row mb-3">
+    <div class="col-sm-10 offset-sm-2">
+        <div class="form-check{{if .HasAttribute "role"}} form-switch{{end}}">
+            <input type="checkbox" name="{{.Name}}" value="true" id="id_{{.Name}}"{{attributes .Attributes}}
+                   {{if not (.HasAttribute "class")}}class="form-check-input{{if .GetErrors}} is-invalid{{end}}"{{end}}
+                   {{if .IsChecked}} checked{{end}}{{if .GetErrors}} aria-describedby="id_{{.Name}}_feedback"{{end}} />
+            <label class="form-check-label" for="id_{{.Name}}">{{.RenderLabel}}</label>
+        </div>
+        {{template "errors" .}}
+    </div>
+</div>
+{{end}}
+
+{{define "button"}}
+<div class="row mb-3">
+    <div class="col-sm-10 offset-sm-2">
+        <button type="submit"{{if not (.HasAttribute "class")}} class="btn btn-primary"{{end}}{{attributes .Attributes}}>{{.RenderLabel}}</button>
+    </div>
+</div>
+{{end}}
+
+{{define "submit"}}
+<div class="row mb-3">
+    <div class="col-sm-10 offset-sm-2">
+        <input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="submit"{{if not (.HasAttribute "class")}} class="btn btn-primary"{{end}}{{attributes .Attributes}} value="{{.Label}}">
+    </div>
+</div>
+{{end}}
+
+{{define "image"}}
+<div class="row mb-3">
+    <div class="col-sm-10 offset-sm-2">
+        <input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="image"{{attributes .Attributes}}>
+    </div>
+</div>
+{{end}}
+{{define "captcha"}}
+{{$challenge := .GetChallenge}}
+<div class="row mb-3">
+    {{template "label" .}}
+    <div class="col-sm-10">
+        {{$challenge.Widget}}
+        {{range $challenge.Hidden}}<input type="hidden" name="{{.Key}}" value="{{.Value}}" />{{end}}
+        {{if $challenge.Answer}}
+        <input name="{{.Name}}" type="text" value="" autocomplete="off"{{attributes .Attributes .GetConstraintAttributes}}
+        {{if not (.HasAttribute "class")}}class="form-control{{if .GetErrors}} is-invalid{{end}}"{{end}}
+        id="id_{{.Name}}"{{if .GetErrors}} aria-describedby="id_{{.Name}}_feedback"{{end}} />
+        {{end}}
+        {{template "errors" .}}
+    </div>
+</div>
+{{end}}
+`
+
+// ThemeSemantic renders framework free, semantic markup. Every control has a
+// label, option groups are fieldsets and errors are linked to their controls
+// through aria-describedby.
+var ThemeSemantic Theme = `
+{{define "label"}}
+<label for="id_{{.Name}}">{{.RenderLabel}}</label>
+{{end}}
+
+{{define "errors"}}
+{{if .GetErrors}}
+<ul id="id_{{.Name}}_errors" class="errors">
+    {{range .GetErrorMessages}}
+    <li>{{.}}</li>
+    {{end}}
+</ul>
+{{end}}
+{{end}}
+
+{{define "text"}}
+<div class="field{{if .GetErrors}} field-invalid{{end}}">
+    {{template "label" .}}
+    <input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}" id="id_{{.Name}}"{{attributes .Attributes .GetConstraintAttributes}}
+    {{if .IsRequired}} aria-required="true"{{end}}{{if .GetErrors}} aria-invalid="true" aria-describedby="id_{{.Name}}_errors"{{end}} />
+    {{template "errors" .}}
+</div>
+{{end}}
+
+{{define "file"}}
+<div class="field{{if .GetErrors}} field-invalid{{end}}">
+    {{template "label" .}}
+    {{if .GetFile}}
+    <p>
+        <a href="{{.GetFile.Location}}" target="_blank">{{.GetFile.Name}}</a>
+        {{if ne .GetDeletionUrl ""}}
+        <a href="{{.GetDeletionUrl}}" class="delete" aria-label="Delete {{.GetFile.Name}}">Delete</a>
+        {{end}}
+    </p>
+    {{end}}
+    <input name="{{.Name}}" type="file"{{if .IsMultiple}} multiple{{end}} id="id_{{.Name}}"{{attributes .Attributes .GetConstraintAttributes}}
+    {{if .IsRequired}} aria-required="true"{{end}}{{if .GetErrors}} aria-invalid="true" aria-describedby="id_{{.Name}}_errors"{{end}} />
+    {{template "errors" .}}
+</div>
+{{end}}
+
+{{define "hidden"}}
+<input name="{{.Name}}" type="{{.Type}}" value="{{.Value}}" id="id_{{.Name}}"{{attributes .Attributes}} />
+{{end}}
+
+{{define "textarea"}}
+<div class="field{{if .GetErrors}} field-invalid{{end}}">
+    {{template "label" .}}
+    <textarea name="{{.Name}}" id="id_{{.Name}}"{{attributes .Attributes .GetConstraintAttributes}}
+    {{if .IsRequired}} aria-required="true"{{end}}{{if .GetErrors}} aria-invalid="true" aria-describedby="id_{{.Name}}_errors"{{end}}>
+{{.Value}}</textarea>
+    {{template "errors" .}}
+</div>
+{{end}}
+
+{{define "select"}}
+<div class="field{{if .GetErrors}} field-invalid{{end}}">
+    {{template "label" .}}
+    <select name="{{.Name}}"{{if .IsMultiple}} multiple{{end}} id="id_{{.Name}}"{{attributes .Attributes .GetConstraintAttributes}}
+    {{if .IsRequired}} aria-required="true"{{end}}{{if .GetErrors}} aria-invalid="true" aria-describedby="id_{{.Name}}_errors"{{end}}>
+        {{range .ValueOptions}}
+        <option value="{{.Value}}"{{if .Selected}} selected{{end}}{{if .Disabled}} disabled{{end}}>{{.Label}}</option>
+        {{end}}
+    </select>
+    {{template "errors" .}}
+</div>
+{{end}}
+
+{{define "radio"}}
+<fieldset class="field{{if .GetErrors}} field-invalid{{end}}" role="radiogroup"
+{{if .IsRequired}} aria-required="true"{{end}}{{if .GetErrors}} aria-invalid="true" aria-describedby="id_{{.Name}}_errors"{{end}}>
+    <legend>{{.RenderLabel}}</legend>
+    {{range $index, $option := .ValueOptions}}
+    <div>
+        <input type="radio" name="{{$.Name}}" value="{{$option.Value}}" id="id_{{$.Name}}_{{$index}}"{{attributes $.Attributes $.GetConstraintAttributes}}
+        {{if eq $.Value $option.Value}} checked{{else}}{{if $option.Selected}} checked{{end}}{{end}}{{if $option.Disabled}} disabled{{end}} />
+        <label for="id_{{$.Name}}_{{$index}}">{{$option.Label}}</label>
+    </div>
+    {{end}}
+    {{template "errors" .}}
+</fieldset>
+{{end}}
+
+{{define "multicheckbox"}}
+<fieldset class="field{{if .GetErrors}} field-invalid{{end}}" role="group"
+{{if .IsRequired}} aria-required="true"{{end}}{{if .GetErrors}} aria-invalid="true" aria-describedby="id_{{.Name}}_errors"{{end}}>
+    <legend>{{.RenderLabel}}</legend>
+    {{range $index, $option := .ValueOptions}}
+    <div>
+        <input type="checkbox" name="{{$.Name}}[]" value="{{$option.Value}}" id="id_{{$.Name}}_{{$index}}"{{attributes $.Attributes}}
+        {{if $.IsCheckedInValues $option.Value}} checked{{else}}{{if $option.Selected}} checked{{end}}{{end}}{{if $option.Disabled}} disabled{{end}}
+        {{if $.GetErrors}} aria-invalid="true"{{end}} />
+        <label for="id_{{$.Name}}_{{$index}}">{{$option.Label}}</label>
+    </div>
+    {{end}}
+    {{template "errors" .}}
+</fieldset>
+{{end}}
+
+{{define "checkbox"}}
+<div class="field{{if .GetErrors}} field-invalid{{end}}">
+    <input type="checkbox" name="{{.Name}}" value="true" id="id_{{.Name}}"{{attributes .Attributes}}{{if .IsChecked}} checked{{end}}
+    {{if .IsRequired}} aria-required="true"{{end}}{{if .GetErrors}} aria-invalid="true" aria-describedby="id_{{.Name}}_errors"{{end}} />
+    {{template "label" .}}
+    {{template "errors" .}}
+</div>
+{{end}}
+
+{{define "button"}}
+<div class="field">
+    <button type="submit"{{if .Name}} name="{{.Name}}"{{end}}{{attributes .Attributes}}>{{.RenderLabel}}</button>
+</div>
+{{end}}
+
+{{define "submit"}}
+<div class="field">
+    <input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="submit" value="{{.Label}}"{{attributes .Attributes}} />
+</div>
+{{end}}
+
+{{define "image"}}
+<div class="field">
+    <input name="{{if .Name}}{{.Name}}{{else}}submit{{end}}" type="image"{{if not (.HasAttribute "alt")}} alt="{{.Label}}"{{end}}{{attributes .Attributes}} />
+</div>
+{{end}}
+{{define "captcha"}}
+{{$challenge := .GetChallenge}}
+<div class="field{{if .GetErrors}} field-invalid{{end}}">
+    {{if $challenge.Answer}}{{template "label" .}}{{else}}<span class="label">{{.RenderLabel}}</span>{{end}}
+    {{$challenge.Widget}}
+    {{range $challenge.Hidden}}<input type="hidden" name="{{.Key}}" value="{{.Value}}" />{{end}}
+    {{if $challenge.Answer}}
+    <input name="{{.Name}}" type="text" value="" autocomplete="off" id="id_{{.Name}}"{{attributes .Attributes .GetConstraintAttributes}}
+    {{if .IsRequired}} aria-required="true"{{end}}{{if .GetErrors}} aria-invalid="true" aria-describedby="id_{{.Name}}_errors"{{end}} />
+    {{end}}
+    {{template "errors" .}}
+</div>
+{{end}}
+`
+
+// defaultFormTemplate is used by themes that do not define their own form
+// wrapper.
+const defaultFormTemplate = `{{define "form"}}
+<form action="{{.GetAction}}" method="{{.GetMethod}}"{{if .GetId}} id="{{.GetId}}"{{end}}{{if and .IsMultipart (not (.HasAttribute "enctype"))}} enctype="multipart/form-data"{{end}}{{if .IsNoValidate}} novalidate{{end}}{{attributes .GetAttributes}}>
+{{template "formerrors" .}}
+{{.RenderElements}}
+</form>
+{{end}}`
+
+// defaultFormErrorsTemplate renders the errors of the form which do not belong
+// to an element, for themes that do not define their own formerrors block.
+const defaultFormErrorsTemplate = `{{define "formerrors"}}
+{{if .GetFormErrors}}
+<ul class="form-errors" role="alert">
+    {{range .GetFormErrorMessages}}
+    <li>{{.}}</li>
+    {{end}}
+</ul>
+{{end}}
+{{end}}`
+
+// defaultCollectionTemplate is used by themes that do not define their own
+// collection block. The rows are rendered with the element blocks of the theme.
+const defaultCollectionTemplate = `{{define "collection"}}
+<fieldset id="id_{{.Name}}" class="goform-collection" data-collection="{{.Name}}" data-min="{{.Min}}" data-max="{{.Max}}" data-index="{{len .Rows}}" data-placeholder="__index__"{{attributes .Attributes}}>
+    <legend>{{.RenderLabel}}</legend>
+    <div class="goform-collection-rows">
+    {{range $index, $row := .Rows}}
+    <div class="goform-collection-row" data-index="{{$index}}">
+    {{$row.RenderElements}}
+    </div>
+    {{end}}
+    </div>
+    <template class="goform-collection-prototype">
+    <div class="goform-collection-row" data-index="__index__">
+    {{.RenderPrototype}}
+    </div>
+    </template>
+
+    {{if .GetErrors}}
+    <ul class="goform-collection-errors">
+        {{range .GetErrorMessages}}
+        <li>{{.}}</li>
+        {{end}}
+    </ul>
+    {{end}}
+</fieldset>
+{{end}}`
+
+// defaultSubFormTemplate is used by themes that do not define their own
+// subform block.
+const defaultSubFormTemplate = `{{define "subform"}}
+<fieldset id="id_{{.Name}}" class="goform-subform"{{attributes .Attributes}}>
+    <legend>{{.RenderLabel}}</legend>
+    {{.RenderElements}}
+</fieldset>
+{{end}}`
+
+// defaultBlocks are parsed for the blocks a theme does not define.
+var defaultBlocks = []struct {
+	name string
+	text string
+}{
+	{"form", defaultFormTemplate},
+	{"formerrors", defaultFormErrorsTemplate},
+	{"collection", defaultCollectionTemplate},
+	{"subform", defaultSubFormTemplate},
+}
+
+func NewTemplate(theme Theme, funcMap template.FuncMap) (*template.Template, error) {
+	t, err := newThemeTemplate(theme, funcMap)
+	if err != nil {
+		return nil, err
+	}
+	for _, block := range defaultBlocks {
+		if t.Lookup(block.name) == nil {
+			if t, err = t.Parse(block.text); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return t, nil
+}
+
+// maxCachedTemplates bounds the compiled themes kept, the oldest is dropped
+// first.
+const maxCachedTemplates = 256
+
+// functionsFingerprint identifies a set of template functions by their names
+// and values, equal sets share one compiled theme. A function is told apart by
+// the address of the function value, reflect.Value.Pointer is the same for
+// every closure of a function literal. A compiled theme keeps its functions
+// alive, so their addresses are not reused while it is cached.
+func functionsFingerprint(funcMap map[string]interface{}) string {
+	if len(funcMap) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(funcMap))
+	for name := range funcMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var fingerprint strings.Builder
+	for _, name := range names {
+		// an interface holding a func keeps the address of the function
+		// value in its data word
+		function := funcMap[name]
+		address := uintptr((*[2]unsafe.Pointer)(unsafe.Pointer(&function))[1])
+		fingerprint.WriteString(name + "=" + strconv.FormatUint(uint64(address), 16) + ";")
+	}
+	return fingerprint.String()
+}
+
+// newThemeTemplate compiles the blocks of a theme built by ExtendTheme or
+// LoadTheme from their parse trees, which keep the file and line they were
+// parsed from, and parses other themes.
+func newThemeTemplate(theme Theme, funcMap template.FuncMap) (*template.Template, error) {
+	t := template.New("goform").Funcs(defaultTemplateFunctions).Funcs(funcMap)
+	blocks, ok := themeBlocks.get(theme)
+	if !ok {
+		return t.Parse(string(theme))
+	}
+	for name, tree := range blocks {
+		// html/template rewrites the trees it escapes, so every template
+		// gets copies
+		tree = tree.Copy()
+		tree.Name = name
+		if err := checkFunctions(tree, funcMap); err != nil {
+			return nil, err
+		}
+		if _, err := t.AddParseTree(name, tree); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// builtinFunctions are the functions of text/template.
+var builtinFunctions = map[string]bool{
+	"and": true, "call": true, "html": true, "index": true, "slice": true, "js": true, "len": true,
+	"not": true, "or": true, "print": true, "printf": true, "println": true, "urlquery": true,
+	"eq": true, "ge": true, "gt": true, "le": true, "lt": true, "ne": true,
+}
+
+// checkFunctions reports a function the tree calls which is not defined, as
+// parsing a theme would. The trees of themes are parsed without the check.
+func checkFunctions(tree *parse.Tree, funcMap template.FuncMap) error {
 	var err error
-	t, err := template.New("goform").Funcs(funcMap).Parse(string(theme))
+	var walk func(node parse.Node)
+	walk = func(node parse.Node) {
+		if err != nil || node == nil || reflect.ValueOf(node).IsNil() {
+			return
+		}
+		switch node := node.(type) {
+		case *parse.ListNode:
+			for _, n := range node.Nodes {
+				walk(n)
+			}
+		case *parse.ActionNode:
+			walk(node.Pipe)
+		case *parse.IfNode:
+			walk(node.Pipe)
+			walk(node.List)
+			walk(node.ElseList)
+		case *parse.RangeNode:
+			walk(node.Pipe)
+			walk(node.List)
+			walk(node.ElseList)
+		case *parse.WithNode:
+			walk(node.Pipe)
+			walk(node.List)
+			walk(node.ElseList)
+		case *parse.TemplateNode:
+			walk(node.Pipe)
+		case *parse.PipeNode:
+			for _, cmd := range node.Cmds {
+				walk(cmd)
+			}
+		case *parse.CommandNode:
+			for _, arg := range node.Args {
+				walk(arg)
+			}
+		case *parse.ChainNode:
+			walk(node.Node)
+		case *parse.IdentifierNode:
+			if _, ok := funcMap[node.Ident]; !ok && !builtinFunctions[node.Ident] && defaultTemplateFunctions[node.Ident] == nil {
+				location, _ := tree.ErrorContext(node)
+				err = fmt.Errorf("template: %s: function %q not defined", location, node.Ident)
+			}
+		}
+	}
+	walk(tree.Root)
+	return err
+}
+
+// templateCacheKey picks the compiled themes by the length and the ends of the
+// theme, which are cheaper to hash than the whole theme, and the fingerprint
+// of the functions. The themes of the candidates are compared in full, which
+// takes no time for the very same string.
+type templateCacheKey struct {
+	length    int
+	ends      string
+	functions string
+}
+
+func newTemplateCacheKey(theme Theme, functions string) templateCacheKey {
+	const n = 32
+	ends := string(theme)
+	if len(ends) > 2*n {
+		ends = ends[:n] + ends[len(ends)-n:]
+	}
+	return templateCacheKey{length: len(theme), ends: ends, functions: functions}
+}
+
+type templateCacheEntry struct {
+	key      templateCacheKey
+	theme    Theme
+	template *template.Template
+}
+
+// templateCache holds compiled themes so that every Render does not parse the
+// whole theme again.
+type templateCache struct {
+	mutex     sync.RWMutex
+	templates map[templateCacheKey][]*list.Element
+	order     *list.List
+}
+
+var compiledTemplates = newTemplateCache()
+
+func newTemplateCache() *templateCache {
+	return &templateCache{templates: map[templateCacheKey][]*list.Element{}, order: list.New()}
+}
+
+func (cache *templateCache) lookup(key templateCacheKey, theme Theme) *template.Template {
+	for _, e := range cache.templates[key] {
+		if entry := e.Value.(*templateCacheEntry); entry.theme == theme {
+			return entry.template
+		}
+	}
+	return nil
+}
+
+func (cache *templateCache) get(theme Theme, funcMap map[string]interface{}) (*template.Template, error) {
+	key := newTemplateCacheKey(theme, functionsFingerprint(funcMap))
+	cache.mutex.RLock()
+	t := cache.lookup(key, theme)
+	cache.mutex.RUnlock()
+	if t != nil {
+		return t, nil
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if t := cache.lookup(key, theme); t != nil {
+		return t, nil
+	}
+	t, err := NewTemplate(theme, funcMap)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	return t
+	if cache.order.Len() >= maxCachedTemplates {
+		cache.remove(cache.order.Front())
+	}
+	entry := &templateCacheEntry{key: key, theme: theme, template: t}
+	cache.templates[key] = append(cache.templates[key], cache.order.PushBack(entry))
+	return t, nil
+}
+
+func (cache *templateCache) remove(e *list.Element) {
+	key := e.Value.(*templateCacheEntry).key
+	cache.order.Remove(e)
+	candidates := cache.templates[key]
+	for i, candidate := range candidates {
+		if candidate == e {
+			candidates = append(candidates[:i], candidates[i+1:]...)
+			break
+		}
+	}
+	if len(candidates) == 0 {
+		delete(cache.templates, key)
+		return
+	}
+	cache.templates[key] = candidates
+}
+
+func (cache *templateCache) invalidateTheme(theme Theme) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	for e := cache.order.Front(); e != nil; {
+		next := e.Next()
+		if e.Value.(*templateCacheEntry).theme == theme {
+			cache.remove(e)
+		}
+		e = next
+	}
+}
+
+func (cache *templateCache) reset() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.templates = map[templateCacheKey][]*list.Element{}
+	cache.order.Init()
+}
+
+// ResetTemplateCache drops every compiled theme, they are parsed again on the
+// next render.
+func ResetTemplateCache() {
+	compiledTemplates.reset()
 }
 
+// renderTemplate is the string returning convenience used by Render. A failing
+// render results in the error as an HTML comment, RenderTo returns it instead.
 func renderTemplate(typ ElementType, element ElementInterface) string {
-	funcMap := template.FuncMap(element.GetTemplateFunctions())
-	t := NewTemplate(element.GetTheme(), funcMap)
 	var buffer bytes.Buffer
-	err := t.ExecuteTemplate(&buffer, string(typ), element)
-	if err != nil {
-		panic(err)
+	if err := renderTemplateTo(&buffer, typ, element); err != nil {
+		return renderError(err)
 	}
 	return buffer.String()
 }
+
+// renderError writes err as an HTML comment, so a broken theme shows up in the
+// page source instead of leaving a blank page.
+func renderError(err error) string {
+	message := strings.Replace(template.HTMLEscapeString(err.Error()), "--", "&#45;&#45;", -1)
+	return "<!-- goform: " + message + " -->"
+}
+
+func renderTemplateTo(w io.Writer, typ ElementType, element ElementInterface) error {
+	return renderConditionalTo(w, element, func(w io.Writer) error {
+		return renderElementTo(w, typ, element)
+	})
+}
+
+func renderElementTo(w io.Writer, typ ElementType, element ElementInterface) error {
+	theme := element.GetTheme()
+	if loader := element.GetThemeLoader(); loader != nil {
+		var err error
+		if theme, err = loader.Theme(); err != nil {
+			return err
+		}
+	}
+	if override := element.GetThemeOverride(); override != "" {
+		var err error
+		if theme, err = extendThemeCached(theme, override); err != nil {
+			return err
+		}
+	}
+	return renderThemeTo(w, theme, element.GetTemplateFunctions(), string(typ), element)
+}
+
+func renderThemeTo(w io.Writer, theme Theme, funcMap map[string]interface{}, name string, data interface{}) error {
+	t, err := compiledTemplates.get(theme, funcMap)
+	if err != nil {
+		return err
+	}
+	return t.ExecuteTemplate(w, name, data)
+}
+
+// renderAttributes writes element attributes into a start tag. Attribute names
+// that are not plain HTML names are dropped, as are event handlers, srcdoc and
+// srcset unless they are made with TrustedAttribute. Values are escaped, URL
+// valued attributes and style are sanitized the same way html/template treats
+// href, src and style. When several sets are given an attribute of an earlier
+// set wins.
+func renderAttributes(attributeSets ...[]*Attribute) template.HTMLAttr {
+	var buffer bytes.Buffer
+	written := map[string]bool{}
+	for _, attributes := range attributeSets {
+		for _, attribute := range attributes {
+			if attribute == nil || !attributeName.MatchString(attribute.Key) {
+				continue
+			}
+			key := strings.ToLower(attribute.Key)
+			if written[key] {
+				continue
+			}
+			if !attribute.trusted && (strings.HasPrefix(key, "on") || scriptAttributes[key]) {
+				continue
+			}
+			written[key] = true
+			value := template.HTMLEscapeString(attribute.Value)
+			switch {
+			case attribute.trusted:
+			case urlAttributes[key]:
+				value = template.HTMLEscapeString(sanitizeUrl(attribute.Value))
+			case key == "style":
+				value = sanitizeStyle(attribute.Value)
+			}
+			buffer.WriteString(" " + attribute.Key + `="` + value + `"`)
+		}
+	}
+	return template.HTMLAttr(buffer.String())
+}
+
+type styleDeclaration struct {
+	Property string
+	Value    string
+}
+
+// sanitizeStyle returns the escaped value of an untrusted style attribute.
+// Each declaration is written by html/template, which replaces properties and
+// values that could load resources or run script, e.g. url() or expression(),
+// with ZgotmplZ. Browsers ignore those declarations and apply the others.
+func sanitizeStyle(style string) string {
+	var declarations []styleDeclaration
+	for _, declaration := range strings.Split(style, ";") {
+		property, value, ok := strings.Cut(declaration, ":")
+		if !ok {
+			continue
+		}
+		declarations = append(declarations, styleDeclaration{strings.TrimSpace(property), strings.TrimSpace(value)})
+	}
+	var buffer strings.Builder
+	if err := styleTemplate.Execute(&buffer, declarations); err != nil {
+		return "ZgotmplZ"
+	}
+	value := strings.TrimPrefix(buffer.String(), `<p style="`)
+	return strings.TrimSuffix(value, `">`)
+}
+
+func sanitizeUrl(s string) string {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return unsafeUrl
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https", "mailto", "tel":
+		return s
+	}
+	return unsafeUrl
+}
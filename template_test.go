@@ -0,0 +1,547 @@
+package goform
+
+import (
+	"errors"
+	"html/template"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testThemes = map[string]Theme{
+	"bootstrap4alpha6Inline":  ThemeBootstrap4alpha6Inline,
+	"bootstrap4alpha6Textual": ThemeBootstrap4alpha6Textual,
+	"bootstrap4alpha6":        ThemeBootstrap4alpha6,
+	"bootstrap4Inline":        ThemeBootstrap4Inline,
+	"bootstrap4Textual":       ThemeBootstrap4Textual,
+	"bootstrap4":              ThemeBootstrap4,
+	"bootstrap5":              ThemeBootstrap5,
+	"bootstrap5Inline":        ThemeBootstrap5Inline,
+	"bootstrap5Horizontal":    ThemeBootstrap5Horizontal,
+	"semantic":                ThemeSemantic,
+}
+
+const hostile = `"><script>pwn()</script>`
+
+// injected matches the hostile values which made it into an attribute running
+// script, they are escaped text everywhere else.
+var injected = regexp.MustCompile(`(?i)<script|javascript:|\s(on[a-z]+|style|srcdoc|srcset)="[^"]*pwn`)
+
+// xssElements returns one element of each type with the attributes, label
+// and options given.
+func xssElements(label string, attributes func() []*Attribute, options func() []*ValueOption) []ElementInterface {
+	file := NewFileElement("file", label, attributes(), nil, nil, "javascript:pwn()")
+	file.SetFile(&File{Name: label, Location: "javascript:pwn()"})
+	return []ElementInterface{
+		NewTextElement("text", label, attributes(), nil, nil),
+		NewTextareaElement("textarea", label, attributes(), nil, nil),
+		NewPasswordElement("password", label, attributes(), nil, nil),
+		NewNumberElement("number", label, attributes(), nil, nil),
+		NewHiddenElement("hidden", attributes(), nil, nil),
+		NewCheckboxElement("checkbox", label, attributes(), nil, nil),
+		NewSelectElement("select", label, attributes(), options(), nil, nil),
+		NewMultiSelectElement("multiselect", label, attributes(), options(), nil, nil),
+		NewRadioElement("radio", label, attributes(), options(), nil, nil),
+		NewMultiCheckboxElement("multicheckbox", label, attributes(), options(), nil, nil),
+		file,
+		NewMultiFileElement("multifile", label, attributes(), nil, nil),
+		NewButtonElement("button", label, attributes()),
+		NewSubmitElement("submit", label, attributes()),
+		NewImageElement("image", label, attributes()),
+	}
+}
+
+func TestRenderEscapesHostileInput(t *testing.T) {
+	noAttributes := func() []*Attribute { return nil }
+	noOptions := func() []*ValueOption { return []*ValueOption{{Value: "a", Label: "a"}} }
+	tests := []struct {
+		name       string
+		label      string
+		attributes func() []*Attribute
+		options    func() []*ValueOption
+		value      string
+		error      string
+	}{
+		{name: "label", label: hostile, attributes: noAttributes, options: noOptions},
+		{name: "value", label: "Label", attributes: noAttributes, options: noOptions, value: hostile},
+		{name: "error", label: "Label", attributes: noAttributes, options: noOptions, error: hostile},
+		{name: "option", label: "Label", attributes: noAttributes, options: func() []*ValueOption {
+			return []*ValueOption{{Value: hostile, Label: hostile}}
+		}},
+		{name: "attribute name", label: "Label", options: noOptions, attributes: func() []*Attribute {
+			return []*Attribute{{Key: `x` + hostile, Value: "1"}, {Key: `onclick"`, Value: "pwn()"}}
+		}},
+		{name: "attribute value", label: "Label", options: noOptions, attributes: func() []*Attribute {
+			return []*Attribute{{Key: "data-x", Value: hostile}, {Key: "title", Value: hostile}}
+		}},
+		{name: "event handler", label: "Label", options: noOptions, attributes: func() []*Attribute {
+			return []*Attribute{{Key: "onfocus", Value: "pwn()"}, {Key: "OnClick", Value: "pwn()"}, {Key: "onmouseover", Value: "pwn()"}}
+		}},
+		{name: "style and markup", label: "Label", options: noOptions, attributes: func() []*Attribute {
+			return []*Attribute{{Key: "style", Value: "background:url(pwn())"}, {Key: "srcdoc", Value: "<p>pwn()</p>"}, {Key: "srcset", Value: "pwn() 1x"}}
+		}},
+		{name: "url", label: "Label", options: noOptions, attributes: func() []*Attribute {
+			return []*Attribute{{Key: "formaction", Value: "javascript:pwn()"}, {Key: "href", Value: " JavaScript:pwn()"}, {Key: "xlink:href", Value: "javascript:pwn()"}}
+		}},
+	}
+	for themeName, theme := range testThemes {
+		for _, test := range tests {
+			t.Run(themeName+"/"+test.name, func(t *testing.T) {
+				form := NewGoForm()
+				form.SetTheme(theme)
+				for _, attribute := range test.attributes() {
+					form.AddAttribute(attribute)
+				}
+				for _, e := range xssElements(test.label, test.attributes, test.options) {
+					form.Add(e)
+					if test.value != "" {
+						e.SetValue(test.value)
+					}
+					if test.error != "" {
+						e.AddError(test.error, nil)
+					}
+				}
+				if test.error != "" {
+					form.AddFormError(test.error, nil)
+				}
+
+				out := form.Render()
+				if unsafe := injected.FindString(out); unsafe != "" {
+					t.Errorf("rendered %q:\n%s", unsafe, out)
+				}
+				if !strings.Contains(out, "</form>") {
+					t.Errorf("rendered no form:\n%s", out)
+				}
+			})
+		}
+	}
+}
+
+func TestRenderAttributes(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes []*Attribute
+		want       template.HTMLAttr
+	}{
+		{"escaped", []*Attribute{{Key: "title", Value: `a"b<c`}}, ` title="a&#34;b&lt;c"`},
+		{"invalid name", []*Attribute{{Key: `a"b`, Value: "1"}}, ``},
+		{"event handler", []*Attribute{{Key: "onClick", Value: "pwn()"}}, ``},
+		{"style", []*Attribute{{Key: "style", Value: "color:red"}}, ` style="color: red;"`},
+		{"style declarations", []*Attribute{{Key: "Style", Value: "width:100px; margin:0 auto;"}}, ` Style="width: 100px; margin: 0 auto;"`},
+		{"style url", []*Attribute{{Key: "style", Value: "width:1px;background:url(javascript:pwn())"}}, ` style="width: 1px; background: ZgotmplZ;"`},
+		{"style expression", []*Attribute{{Key: "style", Value: "width:expression(pwn())"}}, ` style="width: ZgotmplZ;"`},
+		{"style breaking out", []*Attribute{{Key: "style", Value: `color:red" onclick="pwn()`}}, ` style="color: ZgotmplZ;"`},
+		{"url", []*Attribute{{Key: "href", Value: "javascript:pwn()"}}, ` href="#ZgotmplZ"`},
+		{"safe url", []*Attribute{{Key: "href", Value: "/terms?a=1&b=2"}}, ` href="/terms?a=1&amp;b=2"`},
+		{"trusted event handler", []*Attribute{TrustedAttribute("onchange", template.JS("this.form.submit()"))}, ` onchange="this.form.submit()"`},
+		{"trusted style", []*Attribute{TrustedAttribute("style", template.CSS("color:red"))}, ` style="color:red"`},
+		{"trusted url", []*Attribute{TrustedAttribute("href", template.URL("tel:+90"))}, ` href="tel:+90"`},
+		{"dropped is not written", []*Attribute{{Key: "onchange", Value: "pwn()"}, TrustedAttribute("onchange", template.JS("go()"))}, ` onchange="go()"`},
+	}
+	for _, test := range tests {
+		if got := renderAttributes(test.attributes); got != test.want {
+			t.Errorf("%s: renderAttributes() = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestConstraintAttributes(t *testing.T) {
+	tests := []struct {
+		name    string
+		element ElementInterface
+		want    []string
+		absent  []string
+	}{
+		{
+			name:    "validators",
+			element: NewTextElement("x", "X", nil, []ValidatorInterface{&RequiredValidator{}, &MinLengthValidator{Length: 2}, &MaxLengthValidator{Length: 20}, &RegexValidator{Pattern: "[a-z]+"}}, nil),
+			want:    []string{`required="required"`, `minlength="2"`, `maxlength="20"`, `pattern="[a-z]+"`},
+		},
+		{
+			name:    "numbers",
+			element: NewNumberElement("x", "X", nil, []ValidatorInterface{&MinValueValidator{Min: 0.5}, &MaxValueValidator{Max: 100}}, nil),
+			want:    []string{`min="0.5"`, `max="100"`},
+		},
+		{
+			name:    "date",
+			element: NewDateElement("x", "X", nil, []ValidatorInterface{&MinDateValidator{Min: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)}}, nil),
+			want:    []string{`min="2026-01-01"`},
+		},
+		{
+			name:    "file",
+			element: NewFileElement("x", "X", nil, []ValidatorInterface{&FileExtensionValidator{Extensions: []string{"PDF", "png"}}}, nil, ""),
+			want:    []string{`accept=".pdf,.png"`},
+		},
+		{
+			name:    "explicit attribute wins",
+			element: NewTextElement("x", "X", []*Attribute{{Key: "MaxLength", Value: "10"}, {Key: "pattern", Value: "[0-9]+"}}, []ValidatorInterface{&MaxLengthValidator{Length: 20}, &RegexValidator{Pattern: "[a-z]+"}}, nil),
+			want:    []string{`MaxLength="10"`, `pattern="[0-9]+"`},
+			absent:  []string{`maxlength="20"`, `pattern="[a-z]+"`},
+		},
+	}
+	for _, test := range tests {
+		form := NewGoForm()
+		form.Add(test.element)
+		out := test.element.Render()
+		for _, want := range test.want {
+			if strings.Count(out, want) != 1 {
+				t.Errorf("%s: rendered %s %d times:\n%s", test.name, want, strings.Count(out, want), out)
+			}
+		}
+		for _, absent := range test.absent {
+			if strings.Contains(out, absent) {
+				t.Errorf("%s: rendered %s:\n%s", test.name, absent, out)
+			}
+		}
+	}
+}
+
+func TestSetNoValidate(t *testing.T) {
+	newForm := func() *Form {
+		address := NewGoForm()
+		address.Add(NewTextElement("city", "City", nil, []ValidatorInterface{&RequiredValidator{}}, nil))
+		form := NewGoForm()
+		form.Add(NewTextElement("name", "Name", nil, []ValidatorInterface{&RequiredValidator{}}, nil))
+		form.Add(NewSubFormElement("address", "Address", nil, address))
+		items := NewCollectionElement("items", "Items", nil, func() []ElementInterface {
+			return []ElementInterface{NewTextElement("sku", "SKU", nil, []ValidatorInterface{&RequiredValidator{}}, nil)}
+		}, 0, 0)
+		items.AddRow()
+		form.Add(items)
+		return form
+	}
+
+	// the prototype row of the collection is rendered as well
+	form := newForm()
+	if out := form.Render(); strings.Count(out, `required="required"`) != 4 || strings.Contains(out, "novalidate") {
+		t.Errorf("rendered without SetNoValidate:\n%s", out)
+	}
+
+	form.SetNoValidate(true)
+	// elements added afterwards are not validated by the browser either
+	form.Add(NewTextElement("email", "Email", nil, []ValidatorInterface{&RequiredValidator{}}, nil))
+	items, _ := form.Get("items")
+	items.(*CollectionElement).AddRow()
+	out := form.Render()
+	if strings.Contains(out, "required=") {
+		t.Errorf("rendered constraint attributes with SetNoValidate:\n%s", out)
+	}
+	if !strings.Contains(out, " novalidate") {
+		t.Errorf("rendered no novalidate attribute:\n%s", out)
+	}
+	// the server still validates
+	if form.IsValid() {
+		t.Error("IsValid() = true, want false")
+	}
+
+	form.SetNoValidate(false)
+	if out := form.Render(); strings.Count(out, `required="required"`) != 6 || strings.Contains(out, "novalidate") {
+		t.Errorf("rendered after SetNoValidate(false):\n%s", out)
+	}
+}
+
+func TestRenderEnctype(t *testing.T) {
+	fileRow := func() []ElementInterface {
+		return []ElementInterface{NewFileElement("doc", "Doc", nil, nil, nil, "")}
+	}
+	textRow := func() []ElementInterface {
+		return []ElementInterface{NewTextElement("sku", "SKU", nil, nil, nil)}
+	}
+	tests := []struct {
+		name      string
+		element   func() ElementInterface
+		multipart bool
+	}{
+		{"text", func() ElementInterface { return NewTextElement("name", "Name", nil, nil, nil) }, false},
+		{"file", func() ElementInterface { return NewFileElement("doc", "Doc", nil, nil, nil, "") }, true},
+		{"multi file", func() ElementInterface { return NewMultiFileElement("docs", "Docs", nil, nil, nil) }, true},
+		{"sub form", func() ElementInterface {
+			sub := NewGoForm()
+			sub.Add(NewTextElement("city", "City", nil, nil, nil))
+			return NewSubFormElement("address", "Address", nil, sub)
+		}, false},
+		{"sub form with a file", func() ElementInterface {
+			inner := NewGoForm()
+			inner.Add(NewFileElement("photo", "Photo", nil, nil, nil, ""))
+			sub := NewGoForm()
+			sub.Add(NewSubFormElement("inner", "Inner", nil, inner))
+			return NewSubFormElement("outer", "Outer", nil, sub)
+		}, true},
+		// the prototype decides, a collection without rows is multipart as well
+		{"collection", func() ElementInterface { return NewCollectionElement("items", "Items", nil, textRow, 0, 0) }, false},
+		{"collection with a file", func() ElementInterface { return NewCollectionElement("items", "Items", nil, fileRow, 0, 0) }, true},
+	}
+	for _, test := range tests {
+		for name, theme := range testThemes {
+			form := NewGoForm()
+			form.SetTheme(theme)
+			form.Add(NewTextElement("title", "Title", nil, nil, nil))
+			form.Add(test.element())
+			if form.IsMultipart() != test.multipart {
+				t.Errorf("%s: IsMultipart() = %v, want %v", test.name, form.IsMultipart(), test.multipart)
+			}
+			out := form.Render()
+			if got := strings.Count(out, `enctype="multipart/form-data"`) == 1; got != test.multipart {
+				t.Errorf("%s, %s: rendered enctype %v, want %v:\n%s", test.name, name, got, test.multipart, out)
+			}
+
+			// an explicit enctype is kept
+			form.AddAttribute(&Attribute{Key: "enctype", Value: "text/plain"})
+			out = form.Render()
+			if strings.Count(out, "enctype=") != 1 || !strings.Contains(out, `enctype="text/plain"`) {
+				t.Errorf("%s, %s: explicit enctype was not kept:\n%s", test.name, name, out)
+			}
+		}
+	}
+}
+
+func TestTemplateCacheFunctions(t *testing.T) {
+	ResetTemplateCache()
+	functions := template.FuncMap{"greeting": func() string { return "hello" }}
+	theme := Theme(`{{define "text"}}{{greeting}}{{end}}`)
+	newForm := func(functions template.FuncMap) *Form {
+		form := NewGoForm()
+		form.SetTheme(theme)
+		form.SetTemplateFunctions(functions)
+		form.Add(NewTextElement("a", "A", nil, nil, nil))
+		form.Add(NewTextElement("b", "B", nil, nil, nil))
+		return form
+	}
+
+	// forms built per request with the same functions share the template
+	for i := 0; i < 3; i++ {
+		form := newForm(functions)
+		a, _ := form.Get("a")
+		b, _ := form.Get("b")
+		if a.Render() != "hello" || b.Render() != "hello" {
+			t.Fatalf("rendered %q, %q", a.Render(), b.Render())
+		}
+	}
+	if n := compiledTemplates.order.Len(); n != 1 {
+		t.Errorf("compiled %d templates for equal functions, want 1", n)
+	}
+
+	functions["greeting"] = func() string { return "hi" }
+	form := newForm(functions)
+	if a, _ := form.Get("a"); a.Render() != "hi" {
+		t.Errorf("rendered %q after changing the functions, want %q", a.Render(), "hi")
+	}
+
+	// closures of the same literal are different functions
+	for _, name := range []string{"Ada", "Semih"} {
+		greeting := func(name string) func() string {
+			return func() string { return name }
+		}(name)
+		form := newForm(template.FuncMap{"greeting": greeting})
+		if a, _ := form.Get("a"); a.Render() != name {
+			t.Errorf("rendered %q, want %q", a.Render(), name)
+		}
+	}
+}
+
+func TestFunctionsFingerprint(t *testing.T) {
+	upper := func(s string) string { return strings.ToUpper(s) }
+	lower := func(s string) string { return strings.ToLower(s) }
+	tests := []struct {
+		name  string
+		a, b  template.FuncMap
+		equal bool
+	}{
+		{"nil and empty", nil, template.FuncMap{}, true},
+		{"same functions", template.FuncMap{"u": upper, "l": lower}, template.FuncMap{"l": lower, "u": upper}, true},
+		{"other function", template.FuncMap{"u": upper}, template.FuncMap{"u": lower}, false},
+		{"other name", template.FuncMap{"u": upper}, template.FuncMap{"upper": upper}, false},
+		{"more functions", template.FuncMap{"u": upper}, template.FuncMap{"u": upper, "l": lower}, false},
+	}
+	for _, test := range tests {
+		if equal := functionsFingerprint(test.a) == functionsFingerprint(test.b); equal != test.equal {
+			t.Errorf("%s: equal fingerprints = %v, want %v", test.name, equal, test.equal)
+		}
+	}
+}
+
+func TestTemplateCacheBounded(t *testing.T) {
+	ResetTemplateCache()
+	for i := 0; i < maxCachedTemplates+10; i++ {
+		theme := Theme(`{{define "text"}}` + strings.Repeat("x", i) + `{{end}}`)
+		if _, err := compiledTemplates.get(theme, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := compiledTemplates.order.Len(); n != maxCachedTemplates {
+		t.Errorf("cached %d templates, want %d", n, maxCachedTemplates)
+	}
+	entries := 0
+	for _, candidates := range compiledTemplates.templates {
+		entries += len(candidates)
+	}
+	if entries != maxCachedTemplates {
+		t.Errorf("indexed %d templates, want %d", entries, maxCachedTemplates)
+	}
+}
+
+func TestTemplateCacheSameEnds(t *testing.T) {
+	ResetTemplateCache()
+	middle := strings.Repeat(" ", 100)
+	a := Theme(`{{define "text"}}` + middle + "a" + middle + `{{end}}`)
+	b := Theme(`{{define "text"}}` + middle + "b" + middle + `{{end}}`)
+	for _, theme := range []Theme{a, b, a} {
+		var buffer strings.Builder
+		if err := renderThemeTo(&buffer, theme, nil, "text", nil); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := strings.TrimSpace(buffer.String()), strings.TrimSpace(string(theme[len(`{{define "text"}}`):len(theme)-len(`{{end}}`)])); got != want {
+			t.Errorf("rendered %q, want %q", got, want)
+		}
+	}
+}
+
+func benchmarkForm() *Form {
+	form := NewGoForm()
+	form.SetTheme(ThemeBootstrap5)
+	form.Add(NewTextElement("name", "Name", nil, []ValidatorInterface{&RequiredValidator{}}, nil))
+	form.Add(NewEmailElement("email", "Email", nil, nil, nil))
+	form.Add(NewSelectElement("country", "Country", nil, []*ValueOption{{Value: "tr", Label: "Turkey"}}, nil, nil))
+	form.Add(NewCheckboxElement("agree", "Agree", nil, nil, nil))
+	form.Add(NewSubmitElement("submit", "Send", nil))
+	return form
+}
+
+func BenchmarkRenderCached(b *testing.B) {
+	form := benchmarkForm()
+	form.Render()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		form.Render()
+	}
+}
+
+func BenchmarkRenderFunctions(b *testing.B) {
+	functions := template.FuncMap{"upper": strings.ToUpper}
+	for i := 0; i < b.N; i++ {
+		form := benchmarkForm()
+		form.SetTemplateFunctions(functions)
+		form.Render()
+	}
+}
+
+func BenchmarkRenderUncached(b *testing.B) {
+	form := benchmarkForm()
+	for i := 0; i < b.N; i++ {
+		ResetTemplateCache()
+		form.Render()
+	}
+}
+
+var (
+	htmlIds         = regexp.MustCompile(`\sid="([^"]+)"`)
+	labelFors       = regexp.MustCompile(`<label[^>]*\sfor="([^"]+)"`)
+	controls        = regexp.MustCompile(`<(?:input|select|textarea)\s[^>]*>`)
+	skippedControls = regexp.MustCompile(`\stype="(?:hidden|submit|button|image)"`)
+	describedBy     = regexp.MustCompile(`\saria-describedby="([^"]+)"`)
+	groups          = regexp.MustCompile(`(?s)<fieldset[^>]*>(.*?)</fieldset>`)
+)
+
+func TestThemeSemanticAccessibility(t *testing.T) {
+	required := func() []ValidatorInterface { return []ValidatorInterface{&RequiredValidator{}} }
+	options := []*ValueOption{{Value: "a", Label: "A"}, {Value: "b", Label: "B"}}
+	form := NewGoForm()
+	form.SetTheme(ThemeSemantic)
+	form.Add(NewTextElement("text", "Text", nil, required(), nil))
+	form.Add(NewEmailElement("email", "Email", nil, nil, nil))
+	form.Add(NewTextareaElement("textarea", "Textarea", nil, required(), nil))
+	form.Add(NewPasswordElement("password", "Password", nil, nil, nil))
+	form.Add(NewNumberElement("number", "Number", nil, nil, nil))
+	form.Add(NewDateElement("date", "Date", nil, nil, nil))
+	form.Add(NewHiddenElement("hidden", nil, nil, nil))
+	form.Add(NewCheckboxElement("checkbox", "Checkbox", nil, required(), nil))
+	form.Add(NewSelectElement("select", "Select", nil, options, required(), nil))
+	form.Add(NewMultiSelectElement("multiselect", "Multiselect", nil, options, nil, nil))
+	form.Add(NewRadioElement("radio", "Radio", nil, options, required(), nil))
+	form.Add(NewMultiCheckboxElement("multicheckbox", "Multicheckbox", nil, options, required(), nil))
+	form.Add(NewFileElement("file", "File", nil, required(), nil, ""))
+	form.Add(NewMultiFileElement("multifile", "Multifile", nil, nil, nil))
+	form.Add(NewSubmitElement("submit", "Send", nil))
+	form.Add(NewCaptchaElement("captcha", "Captcha", nil, &ArithmeticCaptcha{Secret: []byte("secret")}))
+	form.Add(NewCaptchaElement("widget", "Widget", nil, NewReCaptcha("key", "secret")))
+	form.IsValid()
+	for _, e := range form.GetElements() {
+		if _, ok := e.(*SubmitElement); !ok {
+			e.AddError("Value is not valid", nil)
+		}
+	}
+	out := form.Render()
+
+	ids := map[string]bool{}
+	for _, match := range htmlIds.FindAllStringSubmatch(out, -1) {
+		if ids[match[1]] {
+			t.Errorf("id %q is not unique", match[1])
+		}
+		ids[match[1]] = true
+	}
+	labelled := map[string]bool{}
+	for _, match := range labelFors.FindAllStringSubmatch(out, -1) {
+		if !ids[match[1]] {
+			t.Errorf("label for %q has no control", match[1])
+		}
+		labelled[match[1]] = true
+	}
+	for _, control := range controls.FindAllString(out, -1) {
+		if skippedControls.MatchString(control) {
+			continue
+		}
+		id := htmlIds.FindStringSubmatch(control)
+		if id == nil || !labelled[id[1]] {
+			t.Errorf("control has no label: %s", control)
+		}
+	}
+	for _, match := range describedBy.FindAllStringSubmatch(out, -1) {
+		if !ids[match[1]] {
+			t.Errorf("aria-describedby %q has no element", match[1])
+		}
+	}
+	for _, name := range []string{"radio", "multicheckbox"} {
+		var group string
+		for _, match := range groups.FindAllStringSubmatch(out, -1) {
+			if strings.Contains(match[1], `name="`+name) {
+				group = match[0]
+			}
+		}
+		if !strings.Contains(group, "<legend>") {
+			t.Errorf("%s group has no legend:\n%s", name, group)
+		}
+		if !strings.Contains(group[:strings.Index(group, ">")], `aria-required="true"`) {
+			t.Errorf("required %s group is not marked required:\n%s", name, group)
+		}
+	}
+	if t.Failed() {
+		t.Log(out)
+	}
+}
+
+func TestRenderError(t *testing.T) {
+	form := NewGoForm()
+	form.SetTheme(Theme(`{{define "form"}}<form>{{template "missing" .}}</form>{{end}}` +
+		`{{define "text"}}{{.Value.Missing}}{{end}}`))
+	form.Add(NewTextElement("name", "Name", nil, nil, nil))
+
+	var buffer strings.Builder
+	err := form.RenderTo(&buffer)
+	if err == nil {
+		t.Fatal("RenderTo() error = nil")
+	}
+	want := "<!-- goform: " + template.HTMLEscapeString(err.Error()) + " -->"
+	if got := form.Render(); got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+
+	name, _ := form.Get("name")
+	if got := name.Render(); !strings.HasPrefix(got, "<!-- goform: ") || !strings.HasSuffix(got, " -->") {
+		t.Errorf("element Render() = %q, want the error as a comment", got)
+	}
+
+	if got := renderError(errors.New("a -- b --> <script>")); got != "<!-- goform: a &#45;&#45; b &#45;&#45;&gt; &lt;script&gt; -->" {
+		t.Errorf("renderError() = %q", got)
+	}
+}
@@ -0,0 +1,189 @@
+package goform
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template/parse"
+)
+
+var (
+	ErrThemeNotFound = errors.New("Theme not found")
+)
+
+type themeRegistry struct {
+	mutex  sync.RWMutex
+	themes map[string]Theme
+}
+
+var registeredThemes = &themeRegistry{themes: map[string]Theme{
+	"bootstrap4alpha6-inline":  ThemeBootstrap4alpha6Inline,
+	"bootstrap4alpha6-textual": ThemeBootstrap4alpha6Textual,
+	"bootstrap4alpha6":         ThemeBootstrap4alpha6,
+	"bootstrap4-inline":        ThemeBootstrap4Inline,
+	"bootstrap4-textual":       ThemeBootstrap4Textual,
+	"bootstrap4":               ThemeBootstrap4,
+	"bootstrap5":               ThemeBootstrap5,
+	"bootstrap5-inline":        ThemeBootstrap5Inline,
+	"bootstrap5-horizontal":    ThemeBootstrap5Horizontal,
+	"semantic":                 ThemeSemantic,
+}}
+
+// RegisterTheme makes a theme available by name, replacing any theme that was
+// registered under the same name.
+func RegisterTheme(name string, theme Theme) {
+	registeredThemes.mutex.Lock()
+	defer registeredThemes.mutex.Unlock()
+	registeredThemes.themes[name] = theme
+}
+
+func GetTheme(name string) (Theme, error) {
+	registeredThemes.mutex.RLock()
+	defer registeredThemes.mutex.RUnlock()
+	theme, ok := registeredThemes.themes[name]
+	if !ok {
+		return "", ErrThemeNotFound
+	}
+	return theme, nil
+}
+
+// ExtendRegisteredTheme registers a theme built from the registered base theme
+// and the given overrides.
+func ExtendRegisteredTheme(name string, base string, overrides ...Theme) (Theme, error) {
+	baseTheme, err := GetTheme(base)
+	if err != nil {
+		return "", err
+	}
+	theme, err := ExtendTheme(baseTheme, overrides...)
+	if err != nil {
+		return "", err
+	}
+	RegisterTheme(name, theme)
+	return theme, nil
+}
+
+// ExtendTheme returns the base theme with the blocks defined by the overrides
+// replacing the blocks of the same name. Overrides only need to define the
+// blocks they change, e.g. select, file, or the errors and label partials.
+// Later overrides win over earlier ones.
+func ExtendTheme(base Theme, overrides ...Theme) (Theme, error) {
+	blocks := map[string]*parse.Tree{}
+	for _, theme := range append([]Theme{base}, overrides...) {
+		trees, err := parseThemeBlocks(theme)
+		if err != nil {
+			return "", err
+		}
+		for name, tree := range trees {
+			blocks[name] = tree
+		}
+	}
+
+	return joinThemeBlocks(blocks), nil
+}
+
+// joinThemeBlocks writes the blocks into one theme. The blocks are kept with
+// the theme, so it is compiled from them and errors name the file and line a
+// block was parsed from instead of the line of the joined theme.
+func joinThemeBlocks(blocks map[string]*parse.Tree) Theme {
+	var names []string
+	for name := range blocks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString("{{define " + strconv.Quote(name) + "}}")
+		b.WriteString(blocks[name].Root.String())
+		b.WriteString("{{end}}\n")
+	}
+	theme := Theme(b.String())
+	themeBlocks.add(theme, blocks)
+	return theme
+}
+
+// parseThemeBlocks returns the named blocks of a theme. Template functions are
+// only resolved when the theme is compiled for rendering.
+func parseThemeBlocks(theme Theme) (map[string]*parse.Tree, error) {
+	if blocks, ok := themeBlocks.get(theme); ok {
+		trees := map[string]*parse.Tree{}
+		for name, tree := range blocks {
+			trees[name] = tree
+		}
+		return trees, nil
+	}
+	trees := map[string]*parse.Tree{}
+	t := parse.New("goform")
+	t.Mode = parse.SkipFuncCheck
+	if _, err := t.Parse(string(theme), "", "", trees); err != nil {
+		return nil, err
+	}
+	delete(trees, "goform")
+	return trees, nil
+}
+
+// maxCachedThemes bounds the themes kept by themeBlocks and extendedThemes.
+const maxCachedThemes = 256
+
+var (
+	// themeBlocks are the blocks of the themes built by ExtendTheme and
+	// LoadTheme, they are never changed.
+	themeBlocks = newBoundedCache[Theme, map[string]*parse.Tree](maxCachedThemes)
+	// extendedThemes are the themes of the per element overrides.
+	extendedThemes = newBoundedCache[extendedThemeKey, Theme](maxCachedThemes)
+)
+
+type extendedThemeKey struct {
+	base     Theme
+	override Theme
+}
+
+// extendThemeCached is ExtendTheme for the per element overrides which are
+// applied on every render.
+func extendThemeCached(base Theme, override Theme) (Theme, error) {
+	key := extendedThemeKey{base: base, override: override}
+	if theme, ok := extendedThemes.get(key); ok {
+		return theme, nil
+	}
+	theme, err := ExtendTheme(base, override)
+	if err != nil {
+		return "", err
+	}
+	extendedThemes.add(key, theme)
+	return theme, nil
+}
+
+// boundedCache is a map which drops its oldest entry once it holds max
+// entries.
+type boundedCache[K comparable, V any] struct {
+	mutex   sync.RWMutex
+	max     int
+	entries map[K]V
+	order   []K
+}
+
+func newBoundedCache[K comparable, V any](max int) *boundedCache[K, V] {
+	return &boundedCache[K, V]{max: max, entries: map[K]V{}}
+}
+
+func (cache *boundedCache[K, V]) get(key K) (V, bool) {
+	cache.mutex.RLock()
+	defer cache.mutex.RUnlock()
+	value, ok := cache.entries[key]
+	return value, ok
+}
+
+func (cache *boundedCache[K, V]) add(key K, value V) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if _, ok := cache.entries[key]; !ok {
+		if len(cache.order) >= cache.max {
+			delete(cache.entries, cache.order[0])
+			cache.order = cache.order[1:]
+		}
+		cache.order = append(cache.order, key)
+	}
+	cache.entries[key] = value
+}
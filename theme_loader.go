@@ -0,0 +1,182 @@
+package goform
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"text/template/parse"
+	"time"
+)
+
+var (
+	ErrThemeFilesNotFound = errors.New("No theme files matched the pattern")
+)
+
+// LoadTheme builds a theme from the files of fsys matching pattern, e.g. an
+// embed.FS holding "themes/*.html". A file either defines its blocks with
+// {{define}} or, when it has content outside of them, is itself the block
+// named after the file, so "select.html" defines the select block. Parse
+// errors name the file and line they occurred at.
+func LoadTheme(fsys fs.FS, pattern string) (Theme, error) {
+	theme, _, err := loadThemeFiles(fsys, pattern)
+	return theme, err
+}
+
+// themeReloadInterval is how often a reloading ThemeLoader checks its files.
+const themeReloadInterval = time.Second
+
+// ThemeLoader keeps a theme loaded from an fs.FS. When reload is enabled the
+// files are checked at most once a second while rendering and the theme is
+// parsed again after one of them changed, which lets designers work on the
+// markup without recompiling. Set it on a form with SetThemeLoader.
+type ThemeLoader struct {
+	fsys     fs.FS
+	pattern  string
+	reload   bool
+	mutex    sync.Mutex
+	theme    Theme
+	modTimes map[string]time.Time
+	checked  time.Time
+}
+
+func NewThemeLoader(fsys fs.FS, pattern string, reload bool) (*ThemeLoader, error) {
+	theme, modTimes, err := loadThemeFiles(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+	return &ThemeLoader{
+		fsys:     fsys,
+		pattern:  pattern,
+		reload:   reload,
+		theme:    theme,
+		modTimes: modTimes,
+		checked:  time.Now(),
+	}, nil
+}
+
+// Theme returns the loaded theme, parsing the files again first if reload is
+// enabled and they changed since they were last loaded.
+func (loader *ThemeLoader) Theme() (Theme, error) {
+	loader.mutex.Lock()
+	defer loader.mutex.Unlock()
+	if !loader.reload || time.Since(loader.checked) < themeReloadInterval {
+		return loader.theme, nil
+	}
+	loader.checked = time.Now()
+
+	modTimes, err := themeFileModTimes(loader.fsys, loader.pattern)
+	if err != nil {
+		return "", err
+	}
+	if sameModTimes(modTimes, loader.modTimes) {
+		return loader.theme, nil
+	}
+
+	theme, modTimes, err := loadThemeFiles(loader.fsys, loader.pattern)
+	if err != nil {
+		return "", err
+	}
+	if theme != loader.theme {
+		compiledTemplates.invalidateTheme(loader.theme)
+	}
+	loader.theme = theme
+	loader.modTimes = modTimes
+	return theme, nil
+}
+
+func loadThemeFiles(fsys fs.FS, pattern string) (Theme, map[string]time.Time, error) {
+	modTimes, err := themeFileModTimes(fsys, pattern)
+	if err != nil {
+		return "", nil, err
+	}
+
+	blocks := map[string]*parse.Tree{}
+	definedIn := map[string]string{}
+	for _, name := range sortedKeys(modTimes) {
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return "", nil, err
+		}
+		trees, err := parseThemeFile(name, string(b))
+		if err != nil {
+			return "", nil, err
+		}
+		for block, tree := range trees {
+			if file, ok := definedIn[block]; ok {
+				return "", nil, fmt.Errorf("template: %s: block %q is already defined in %s", name, block, file)
+			}
+			definedIn[block] = name
+			blocks[block] = tree
+		}
+	}
+	return joinThemeBlocks(blocks), modTimes, nil
+}
+
+func parseThemeFile(name string, text string) (map[string]*parse.Tree, error) {
+	trees := map[string]*parse.Tree{}
+	t := parse.New(name)
+	t.Mode = parse.SkipFuncCheck
+	if _, err := t.Parse(text, "", "", trees); err != nil {
+		return nil, err
+	}
+	if tree, ok := trees[name]; ok {
+		delete(trees, name)
+		if parse.IsEmptyTree(tree.Root) {
+			return trees, nil
+		}
+		block := strings.TrimSuffix(path.Base(name), path.Ext(name))
+		if _, ok := trees[block]; ok {
+			return nil, fmt.Errorf("template: %s: block %q is defined by the file and by a define", name, block)
+		}
+		tree.Name = block
+		trees[block] = tree
+	}
+	return trees, nil
+}
+
+func themeFileModTimes(fsys fs.FS, pattern string) (map[string]time.Time, error) {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, ErrThemeFilesNotFound
+	}
+	modTimes := map[string]time.Time{}
+	for _, name := range names {
+		info, err := fs.Stat(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			continue
+		}
+		modTimes[name] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+func sameModTimes(a map[string]time.Time, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, modTime := range a {
+		if other, ok := b[name]; !ok || !other.Equal(modTime) {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedKeys(m map[string]time.Time) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,89 @@
+package goform
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestLoadThemeErrorPositions(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"execution", "<p>\n\n{{.NoSuchField}}</p>", "themes/text.html:3"},
+		{"function", "<p>\n{{noSuchFunction .Name}}</p>", `themes/text.html:2:2: function "noSuchFunction" not defined`},
+		{"define", "{{define \"text\"}}\n<p>\n\n\n{{.NoSuchField}}</p>{{end}}", "themes/text.html:5"},
+	}
+	for _, test := range tests {
+		theme, err := LoadTheme(fstest.MapFS{"themes/text.html": {Data: []byte(test.text)}}, "themes/*.html")
+		if err != nil {
+			t.Fatal(err)
+		}
+		element := NewTextElement("name", "Name", nil, nil, nil)
+		element.SetTheme(theme)
+		err = element.RenderTo(&strings.Builder{})
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: RenderTo() = %v, want an error at %s", test.name, err, test.want)
+		}
+	}
+}
+
+func TestExtendThemeErrorPositions(t *testing.T) {
+	override, err := LoadTheme(fstest.MapFS{"select.html": {Data: []byte("<select>\n{{.NoSuchField}}</select>")}}, "*.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	theme, err := ExtendTheme(ThemeBootstrap5, override)
+	if err != nil {
+		t.Fatal(err)
+	}
+	element := NewSelectElement("country", "Country", nil, nil, nil, nil)
+	element.SetTheme(theme)
+	if err := element.RenderTo(&strings.Builder{}); err == nil || !strings.Contains(err.Error(), "select.html:2") {
+		t.Errorf("RenderTo() = %v, want an error at select.html:2", err)
+	}
+
+	text := NewTextElement("name", "Name", nil, nil, nil)
+	text.SetTheme(theme)
+	if out := text.Render(); !strings.Contains(out, `name="name"`) {
+		t.Errorf("the blocks of the base theme are lost:\n%s", out)
+	}
+}
+
+func TestExtendThemeCachedBounded(t *testing.T) {
+	for i := 0; i < maxCachedThemes+10; i++ {
+		override := Theme(`{{define "text"}}` + strconv.Itoa(i) + `{{end}}`)
+		if _, err := extendThemeCached(ThemeSemantic, override); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := len(extendedThemes.entries); n > maxCachedThemes {
+		t.Errorf("cached %d extended themes, want at most %d", n, maxCachedThemes)
+	}
+	if n := len(themeBlocks.entries); n > maxCachedThemes {
+		t.Errorf("kept the blocks of %d themes, want at most %d", n, maxCachedThemes)
+	}
+}
+
+func TestThemeLoaderReloadThrottled(t *testing.T) {
+	fsys := fstest.MapFS{"text.html": {Data: []byte("one"), ModTime: time.Unix(1, 0)}}
+	loader, err := NewThemeLoader(fsys, "*.html", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	element := NewTextElement("name", "Name", nil, nil, nil)
+	element.SetThemeLoader(loader)
+
+	fsys["text.html"] = &fstest.MapFile{Data: []byte("two"), ModTime: time.Unix(2, 0)}
+	if out := element.Render(); out != "one" {
+		t.Errorf("rendered %q within the reload interval, want %q", out, "one")
+	}
+	loader.checked = time.Now().Add(-themeReloadInterval)
+	if out := element.Render(); out != "two" {
+		t.Errorf("rendered %q after the reload interval, want %q", out, "two")
+	}
+}
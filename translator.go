@@ -0,0 +1,80 @@
+package goform
+
+// Translator maps a message key, the untranslated message of a validator, to
+// a localized message. The localized message may contain the same verbs as the
+// key, they are filled with the message Args.
+type Translator interface {
+	Translate(key string) (string, bool)
+}
+
+// Catalog is a Translator backed by a map of message keys.
+type Catalog map[string]string
+
+func (catalog Catalog) Translate(key string) (string, bool) {
+	message, ok := catalog[key]
+	return message, ok
+}
+
+var CatalogEnglish = Catalog{
+	"This field is required":                       "This field is required",
+	"Value must be greater than %d":                "Value must be greater than %d",
+	"Value must be lower than %d":                  "Value must be lower than %d",
+	"Value must be after than %s":                  "Value must be after %s",
+	"Value must be before than %s":                 "Value must be before %s",
+	"Value length must be greater than %d":         "Value must be at least %d characters long",
+	"Value length must be lower than %d":           "Value must be at most %d characters long",
+	"Value must be valid email address":            "Value must be a valid email address",
+	"Email host must be valid smtp server":         "Email host must be a valid SMTP server",
+	"Values does not matched with %s":              "Value does not match %s",
+	"Value does not match the required format":     "Value does not match the required format",
+	"Captcha could not be verified":                "Captcha could not be verified, please try again",
+	"Captcha answer is not correct":                "Captcha answer is not correct",
+	"At least %d rows are required":                "At least %d rows are required",
+	"At most %d rows are allowed":                  "At most %d rows are allowed",
+	"At least %d files are required":               "At least %d files are required",
+	"At most %d files are allowed":                 "At most %d files are allowed",
+	"Files must be smaller than %d bytes in total": "Files must be smaller than %d bytes in total",
+	"File %s must be smaller than %d bytes":        "File %s must be smaller than %d bytes",
+	"File %s must have one of the extensions %s":   "File %s must have one of the extensions %s",
+	"Element %s not found":                         "Element %s not found",
+	"At least one of %s is required":               "At least one of %s is required",
+	"The sum of %s must be %s":                     "The sum of %s must be %s",
+	"Value must be a whole number":                 "Value must be a whole number",
+	"Value must be a number":                       "Value must be a number",
+	"Value must be true or false":                  "Value must be true or false",
+	"Value must be a valid date":                   "Value must be a valid date",
+	"Value is out of range":                        "Value is out of range",
+	"Value is not valid":                           "Value is not valid",
+}
+
+var CatalogTurkish = Catalog{
+	"This field is required":                       "Bu alan zorunludur",
+	"Value must be greater than %d":                "Değer %d değerinden büyük olmalıdır",
+	"Value must be lower than %d":                  "Değer %d değerinden küçük olmalıdır",
+	"Value must be after than %s":                  "Tarih %s tarihinden sonra olmalıdır",
+	"Value must be before than %s":                 "Tarih %s tarihinden önce olmalıdır",
+	"Value length must be greater than %d":         "Değer en az %d karakter olmalıdır",
+	"Value length must be lower than %d":           "Değer en fazla %d karakter olmalıdır",
+	"Value must be valid email address":            "Geçerli bir e-posta adresi giriniz",
+	"Email host must be valid smtp server":         "E-posta sunucusu geçerli bir SMTP sunucusu olmalıdır",
+	"Values does not matched with %s":              "Değer %s ile eşleşmiyor",
+	"Value does not match the required format":     "Değer istenen biçimde değil",
+	"Captcha could not be verified":                "Doğrulama yapılamadı, lütfen tekrar deneyin",
+	"Captcha answer is not correct":                "Doğrulama cevabı hatalı",
+	"At least %d rows are required":                "En az %d satır girilmelidir",
+	"At most %d rows are allowed":                  "En fazla %d satır girilebilir",
+	"At least %d files are required":               "En az %d dosya yüklenmelidir",
+	"At most %d files are allowed":                 "En fazla %d dosya yüklenebilir",
+	"Files must be smaller than %d bytes in total": "Dosyaların toplam boyutu %d bayttan küçük olmalıdır",
+	"File %s must be smaller than %d bytes":        "%s dosyası %d bayttan küçük olmalıdır",
+	"File %s must have one of the extensions %s":   "%s dosyasının uzantısı %s olmalıdır",
+	"Element %s not found":                         "%s alanı bulunamadı",
+	"At least one of %s is required":               "%s alanlarından en az biri zorunludur",
+	"The sum of %s must be %s":                     "%s alanlarının toplamı %s olmalıdır",
+	"Value must be a whole number":                 "Değer tam sayı olmalıdır",
+	"Value must be a number":                       "Değer sayı olmalıdır",
+	"Value must be true or false":                  "Değer doğru ya da yanlış olmalıdır",
+	"Value must be a valid date":                   "Geçerli bir tarih giriniz",
+	"Value is out of range":                        "Değer izin verilen aralığın dışında",
+	"Value is not valid":                           "Değer geçersiz",
+}
@@ -0,0 +1,82 @@
+package goform
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestMessageFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		message    Message
+		translator Translator
+		want       string
+	}{
+		{"plain", Message{Message: "This field is required"}, nil, "This field is required"},
+		{"args", Message{Message: "Value length must be greater than %d", Args: []interface{}{3}}, nil, "Value length must be greater than 3"},
+		{"english", Message{Message: "Value length must be greater than %d", Args: []interface{}{3}}, CatalogEnglish, "Value must be at least 3 characters long"},
+		{"turkish", Message{Message: "Value length must be greater than %d", Args: []interface{}{3}}, CatalogTurkish, "Değer en az 3 karakter olmalıdır"},
+		{"turkish reordered", Message{Message: "File %s must be smaller than %d bytes", Args: []interface{}{"a.pdf", 1024}}, CatalogTurkish, "a.pdf dosyası 1024 bayttan küçük olmalıdır"},
+		{"missing key", Message{Message: "Address is not deliverable"}, CatalogTurkish, "Address is not deliverable"},
+		{"missing key with args", Message{Message: "Unknown %s", Args: []interface{}{"x"}}, CatalogTurkish, "Unknown x"},
+		{"percent without args", Message{Message: "100% required"}, nil, "100% required"},
+		{"custom", Message{Message: "This field is required"}, Catalog{"This field is required": "Pflichtfeld"}, "Pflichtfeld"},
+	}
+	for _, test := range tests {
+		if got := test.message.Format(test.translator); got != test.want {
+			t.Errorf("%s: Format() = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+var formatVerb = regexp.MustCompile(`%[a-z]`)
+
+func TestCatalogs(t *testing.T) {
+	if len(CatalogTurkish) != len(CatalogEnglish) {
+		t.Errorf("the turkish catalog has %d messages, the english %d", len(CatalogTurkish), len(CatalogEnglish))
+	}
+	for name, catalog := range map[string]Catalog{"english": CatalogEnglish, "turkish": CatalogTurkish} {
+		for key, message := range catalog {
+			if _, ok := CatalogEnglish[key]; !ok {
+				t.Errorf("%s: %q is not in the english catalog", name, key)
+			}
+			want := strings.Join(formatVerb.FindAllString(key, -1), " ")
+			if got := strings.Join(formatVerb.FindAllString(message, -1), " "); got != want {
+				t.Errorf("%s: %q has the verbs %q, want %q", name, message, got, want)
+			}
+		}
+	}
+}
+
+func TestSetTranslator(t *testing.T) {
+	form := newAddressForm()
+	form.Add(NewTextElement("name", "Name", nil, []ValidatorInterface{&MinLengthValidator{Length: 3}}, nil))
+	form.SetTranslator(CatalogTurkish)
+	// elements added afterwards use the translator of the form as well
+	address, _ := form.Get("address")
+	address.(*SubFormElement).Form.Add(NewTextElement("zip", "Zip", nil, []ValidatorInterface{&RequiredValidator{}}, nil))
+	form.AddValidator(&AtLeastOneValidator{Elements: []string{"city"}})
+	form.bindValues(url.Values{"name": {"ab"}})
+
+	if form.IsValid() {
+		t.Fatal("IsValid() = true, want false")
+	}
+	messages := form.GetErrorMessages()
+	if got := messages["name"]; len(got) != 1 || got[0] != "Değer en az 3 karakter olmalıdır" {
+		t.Errorf("name errors = %v", got)
+	}
+	if got := messages["address[zip]"]; len(got) != 1 || got[0] != "Bu alan zorunludur" {
+		t.Errorf("address[zip] errors = %v", got)
+	}
+	if got := form.GetFormErrorMessages(); len(got) != 1 || got[0] != "City alanlarından en az biri zorunludur" {
+		t.Errorf("form errors = %v", got)
+	}
+
+	form.SetTranslator(nil)
+	name, _ := form.Get("name")
+	if got := name.GetErrorMessages(); len(got) != 1 || got[0] != "Value length must be greater than 3" {
+		t.Errorf("untranslated errors = %v", got)
+	}
+}
@@ -0,0 +1,128 @@
+package goform
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// messageCodes are the codes of the built in messages.
+var messageCodes = map[string]string{
+	"This field is required":                       "required",
+	"Value must be greater than %d":                "min",
+	"Value must be lower than %d":                  "max",
+	"Value must be after than %s":                  "min_date",
+	"Value must be before than %s":                 "max_date",
+	"Value length must be greater than %d":         "min_length",
+	"Value length must be lower than %d":           "max_length",
+	"Value must be valid email address":            "email",
+	"Email host must be valid smtp server":         "email_host",
+	"Values does not matched with %s":              "identical",
+	"Value does not match the required format":     "pattern",
+	"Captcha could not be verified":                "captcha",
+	"Captcha answer is not correct":                "captcha",
+	"At least %d rows are required":                "min_rows",
+	"At most %d rows are allowed":                  "max_rows",
+	"At least %d files are required":               "min_files",
+	"At most %d files are allowed":                 "max_files",
+	"Files must be smaller than %d bytes in total": "max_total_size",
+	"File %s must be smaller than %d bytes":        "max_file_size",
+	"File %s must have one of the extensions %s":   "file_extension",
+	"Element %s not found":                         "element_not_found",
+	"At least one of %s is required":               "at_least_one",
+	"The sum of %s must be %s":                     "sum",
+	"Value must be a whole number":                 "integer",
+	"Value must be a number":                       "number",
+	"Value must be true or false":                  "boolean",
+	"Value must be a valid date":                   "date",
+	"Value is out of range":                        "range",
+	"Value is not valid":                           "invalid",
+}
+
+// GetCode returns the code of the message, "invalid" for messages without
+// one.
+func (message Message) GetCode() string {
+	if message.Code != "" {
+		return message.Code
+	}
+	if code, ok := messageCodes[message.Message]; ok {
+		return code
+	}
+	return "invalid"
+}
+
+// ValidationError is an error of an element, or of the form if Field is empty.
+// Field is the full name of the element, e.g. "items[0][sku]", Message is
+// translated and formatted with Params.
+type ValidationError struct {
+	Field   string        `json:"field"`
+	Code    string        `json:"code"`
+	Message string        `json:"message"`
+	Params  []interface{} `json:"params,omitempty"`
+}
+
+type ValidationErrors []ValidationError
+
+func newValidationError(field string, message Message, translator Translator) ValidationError {
+	return ValidationError{
+		Field:   field,
+		Code:    message.GetCode(),
+		Message: message.Format(translator),
+		Params:  message.Args,
+	}
+}
+
+// Errors returns the errors of the elements in their order, including the
+// elements of collections and sub forms, followed by the errors of the form.
+// The errors of a sub form or a collection row are reported on its name.
+func (form *Form) Errors() ValidationErrors {
+	errs := ValidationErrors{}
+	form.collectErrors(&errs)
+	for _, message := range form.errors {
+		errs = append(errs, newValidationError(form.namePrefix, message, form.translator))
+	}
+	return errs
+}
+
+func (form *Form) collectErrors(errs *ValidationErrors) {
+	for _, e := range form.elements {
+		for _, message := range e.GetErrors() {
+			*errs = append(*errs, newValidationError(e.GetName(), message, e.GetTranslator()))
+		}
+		switch e := e.(type) {
+		case *CollectionElement:
+			for _, row := range e.Rows {
+				*errs = append(*errs, row.Errors()...)
+			}
+		case *SubFormElement:
+			*errs = append(*errs, e.Form.Errors()...)
+		}
+	}
+}
+
+// ProblemDetails is a RFC 7807 problem details document of failed validation.
+type ProblemDetails struct {
+	Type     string           `json:"type"`
+	Title    string           `json:"title"`
+	Status   int              `json:"status"`
+	Detail   string           `json:"detail,omitempty"`
+	Instance string           `json:"instance,omitempty"`
+	Errors   ValidationErrors `json:"errors"`
+}
+
+// ProblemDetails returns the errors as a problem details document with status
+// 422 Unprocessable Entity.
+func (errs ValidationErrors) ProblemDetails() ProblemDetails {
+	return ProblemDetails{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusUnprocessableEntity),
+		Status: http.StatusUnprocessableEntity,
+		Errors: errs,
+	}
+}
+
+// Write writes the document with the application/problem+json content type.
+func (problem ProblemDetails) Write(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+	return json.NewEncoder(w).Encode(problem)
+}
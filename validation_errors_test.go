@@ -0,0 +1,140 @@
+package goform
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type discontinuedValidator struct {
+	Validator
+}
+
+func (validator *discontinuedValidator) IsValid() bool {
+	if validator.Value != "old" {
+		return true
+	}
+	validator.Messages = append(validator.Messages, Message{
+		Message: "SKU %s is discontinued",
+		Args:    []interface{}{validator.Value},
+		Code:    "discontinued",
+	})
+	return false
+}
+
+func TestMessageCodes(t *testing.T) {
+	for key := range CatalogEnglish {
+		if _, ok := messageCodes[key]; !ok {
+			t.Errorf("%q has no code", key)
+		}
+	}
+	for key := range messageCodes {
+		if _, ok := CatalogEnglish[key]; !ok {
+			t.Errorf("%q is not in the english catalog", key)
+		}
+	}
+
+	tests := []struct {
+		message Message
+		want    string
+	}{
+		{Message{Message: "This field is required"}, "required"},
+		{Message{Message: "Value length must be greater than %d", Args: []interface{}{3}}, "min_length"},
+		{Message{Message: "This field is required", Code: "custom"}, "custom"},
+		{Message{Message: "Address is not deliverable"}, "invalid"},
+	}
+	for _, test := range tests {
+		if got := test.message.GetCode(); got != test.want {
+			t.Errorf("GetCode(%q) = %q, want %q", test.message.Message, got, test.want)
+		}
+	}
+}
+
+func newErrorsForm() *Form {
+	address := NewGoForm()
+	address.Add(NewTextElement("city", "City", nil, []ValidatorInterface{&RequiredValidator{}}, nil))
+
+	form := NewGoForm()
+	form.Add(NewTextElement("name", "Name", nil, []ValidatorInterface{&RequiredValidator{}, &MinLengthValidator{Length: 3}}, nil))
+	form.Add(NewSubFormElement("address", "Address", nil, address))
+	form.Add(NewCollectionElement("items", "Items", nil, func() []ElementInterface {
+		return []ElementInterface{NewTextElement("sku", "SKU", nil, []ValidatorInterface{&discontinuedValidator{}}, nil)}
+	}, 0, 0))
+	return form
+}
+
+func TestErrors(t *testing.T) {
+	form := newErrorsForm()
+	form.AddValidator(&AtLeastOneValidator{Elements: []string{"phone", "name"}})
+	form.bindValues(url.Values{"name": {"ab"}, "items[0][sku]": {"new"}, "items[1][sku]": {"old"}})
+	if form.IsValid() {
+		t.Fatal("IsValid() = true, want false")
+	}
+	want := ValidationErrors{
+		{Field: "name", Code: "min_length", Message: "Value length must be greater than 3", Params: []interface{}{3}},
+		{Field: "address[city]", Code: "required", Message: "This field is required"},
+		{Field: "items[1][sku]", Code: "discontinued", Message: "SKU old is discontinued", Params: []interface{}{"old"}},
+		{Field: "", Code: "element_not_found", Message: "Element phone not found", Params: []interface{}{"phone"}},
+	}
+	if got := form.Errors(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Errors() = %+v\nwant %+v", got, want)
+	}
+
+	// messages are translated, params are left as they are
+	form.SetTranslator(CatalogTurkish)
+	if got := form.Errors()[0]; got.Message != "Değer en az 3 karakter olmalıdır" || !reflect.DeepEqual(got.Params, []interface{}{3}) {
+		t.Errorf("translated error = %+v", got)
+	}
+
+	form = newErrorsForm()
+	form.bindValues(url.Values{"name": {"Semih"}, "address[city]": {"Ankara"}})
+	if !form.IsValid() {
+		t.Fatalf("IsValid() = false, want true: %v", form.Errors())
+	}
+	if errs := form.Errors(); errs == nil || len(errs) != 0 {
+		t.Errorf("Errors() = %#v, want an empty list", errs)
+	}
+}
+
+func TestProblemDetails(t *testing.T) {
+	errs := ValidationErrors{
+		{Field: "name", Code: "required", Message: "This field is required"},
+		{Field: "items[0][qty]", Code: "min", Message: "Value must be greater than 1", Params: []interface{}{1}},
+	}
+	problem := errs.ProblemDetails()
+	if problem.Type != "about:blank" || problem.Title != "Unprocessable Entity" || problem.Status != http.StatusUnprocessableEntity {
+		t.Errorf("ProblemDetails() = %+v", problem)
+	}
+	problem.Instance = "/orders"
+
+	w := httptest.NewRecorder()
+	if err := problem.Write(w); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/problem+json" {
+		t.Errorf("Content-Type = %q", contentType)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"type":     "about:blank",
+		"title":    "Unprocessable Entity",
+		"status":   float64(422),
+		"instance": "/orders",
+		"errors": []interface{}{
+			map[string]interface{}{"field": "name", "code": "required", "message": "This field is required"},
+			map[string]interface{}{"field": "items[0][qty]", "code": "min", "message": "Value must be greater than 1", "params": []interface{}{float64(1)}},
+		},
+	}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("body = %v\nwant %v", body, want)
+	}
+}
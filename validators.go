@@ -1,9 +1,14 @@
 package goform
 
 import (
+	"fmt"
 	"github.com/semihs/goform/validators"
+	"math"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 type ValidatorInterface interface {
@@ -14,6 +19,12 @@ type ValidatorInterface interface {
 	GetMessages() []Message
 }
 
+// ConstraintValidator is implemented by validators that have an HTML5
+// constraint attribute counterpart, so the browser can check the same rule.
+type ConstraintValidator interface {
+	Constraints() []*Attribute
+}
+
 type Validator struct {
 	Messages []Message
 	Value    string
@@ -21,9 +32,32 @@ type Validator struct {
 	File     *File
 }
 
+// Message is an error message, a translation key with the arguments to format
+// it. Code identifies the failed check, e.g. "required", it defaults to the
+// code of the built in message.
 type Message struct {
 	Message string
 	Args    []interface{}
+	Code    string
+}
+
+// Format translates the message with the given translator, which may be nil,
+// and fills in its Args.
+func (message Message) Format(translator Translator) string {
+	s := message.Message
+	if translator != nil {
+		if translated, ok := translator.Translate(s); ok {
+			s = translated
+		}
+	}
+	if len(message.Args) == 0 {
+		return s
+	}
+	return fmt.Sprintf(s, message.Args...)
+}
+
+func (message Message) String() string {
+	return message.Format(nil)
 }
 
 func (validator *Validator) SetValue(s string) {
@@ -42,6 +76,23 @@ func (validator *Validator) GetMessages() []Message {
 	return validator.Messages
 }
 
+// ClearMessages removes the messages of an earlier validation.
+func (validator *Validator) ClearMessages() {
+	validator.Messages = nil
+}
+
+// messageClearer is implemented by validators which keep their messages, they
+// are cleared before each validation.
+type messageClearer interface {
+	ClearMessages()
+}
+
+func clearMessages(validator ValidatorInterface) {
+	if v, ok := validator.(messageClearer); ok {
+		v.ClearMessages()
+	}
+}
+
 type RequiredValidator struct {
 	Validator
 }
@@ -54,6 +105,10 @@ func (validator *RequiredValidator) IsValid() bool {
 	return false
 }
 
+func (validator *RequiredValidator) Constraints() []*Attribute {
+	return []*Attribute{{Key: "required", Value: "required"}}
+}
+
 type MinValueValidator struct {
 	Min float64
 	Validator
@@ -78,6 +133,10 @@ func (validator *MinValueValidator) IsValid() bool {
 	return true
 }
 
+func (validator *MinValueValidator) Constraints() []*Attribute {
+	return []*Attribute{{Key: "min", Value: strconv.FormatFloat(validator.Min, 'f', -1, 64)}}
+}
+
 type MaxValueValidator struct {
 	Max float64
 	Validator
@@ -102,61 +161,120 @@ func (validator *MaxValueValidator) IsValid() bool {
 	return true
 }
 
+func (validator *MaxValueValidator) Constraints() []*Attribute {
+	return []*Attribute{{Key: "max", Value: strconv.FormatFloat(validator.Max, 'f', -1, 64)}}
+}
+
+// MinDateValidator compares the value with Min. Date and time elements set
+// the layout and location it is parsed with, the default is LayoutDate in UTC.
 type MinDateValidator struct {
 	Min time.Time
 	Validator
+	layout   string
+	location *time.Location
+}
+
+func (validator *MinDateValidator) setLayout(layout string, location *time.Location) {
+	validator.layout = layout
+	validator.location = location
+}
+
+func (validator *MinDateValidator) getLayout() string {
+	if validator.layout == "" {
+		return LayoutDate
+	}
+	return validator.layout
+}
+
+func (validator *MinDateValidator) formatMin() string {
+	if validator.location == nil {
+		return FormatTime(validator.getLayout(), validator.Min)
+	}
+	return FormatTime(validator.getLayout(), validator.Min.In(validator.location))
 }
 
 func (validator *MinDateValidator) IsValid() bool {
-	value, err := time.Parse("2006-01-02", validator.Value)
+	value, err := ParseTime(validator.getLayout(), validator.Value, validator.location)
 	if err != nil {
 		validator.Messages = append(validator.Messages, Message{
 			Message: "Value must be after than %s",
-			Args:    []interface{}{validator.Min.Format("2006-01-02")},
+			Args:    []interface{}{validator.formatMin()},
 		})
 		return false
 	}
 	if value.Before(validator.Min) {
 		validator.Messages = append(validator.Messages, Message{
 			Message: "Value must be after than %s",
-			Args:    []interface{}{validator.Min.Format("2006-01-02")},
+			Args:    []interface{}{validator.formatMin()},
 		})
 		return false
 	}
 	return true
 }
 
+func (validator *MinDateValidator) Constraints() []*Attribute {
+	return []*Attribute{{Key: "min", Value: validator.formatMin()}}
+}
+
+// MaxDateValidator compares the value with Max. Date and time elements set
+// the layout and location it is parsed with, the default is LayoutDate in UTC.
 type MaxDateValidator struct {
 	Max time.Time
 	Validator
+	layout   string
+	location *time.Location
+}
+
+func (validator *MaxDateValidator) setLayout(layout string, location *time.Location) {
+	validator.layout = layout
+	validator.location = location
+}
+
+func (validator *MaxDateValidator) getLayout() string {
+	if validator.layout == "" {
+		return LayoutDate
+	}
+	return validator.layout
+}
+
+func (validator *MaxDateValidator) formatMax() string {
+	if validator.location == nil {
+		return FormatTime(validator.getLayout(), validator.Max)
+	}
+	return FormatTime(validator.getLayout(), validator.Max.In(validator.location))
 }
 
 func (validator *MaxDateValidator) IsValid() bool {
-	value, err := time.Parse("2006-01-02", validator.Value)
+	value, err := ParseTime(validator.getLayout(), validator.Value, validator.location)
 	if err != nil {
 		validator.Messages = append(validator.Messages, Message{
 			Message: "Value must be before than %s",
-			Args:    []interface{}{validator.Max.Format("2006-01-02")},
+			Args:    []interface{}{validator.formatMax()},
 		})
 		return false
 	}
 	if value.After(validator.Max) {
 		validator.Messages = append(validator.Messages, Message{
 			Message: "Value must be before than %s",
-			Args:    []interface{}{validator.Max.Format("2006-01-02")},
+			Args:    []interface{}{validator.formatMax()},
 		})
 		return false
 	}
 	return true
 }
 
+func (validator *MaxDateValidator) Constraints() []*Attribute {
+	return []*Attribute{{Key: "max", Value: validator.formatMax()}}
+}
+
+// MinLengthValidator counts the characters of the value, not its bytes.
 type MinLengthValidator struct {
 	Length int
 	Validator
 }
 
 func (validator *MinLengthValidator) IsValid() bool {
-	if len(validator.Value) < validator.Length {
+	if utf8.RuneCountInString(validator.Value) < validator.Length {
 		validator.Messages = append(validator.Messages, Message{
 			Message: "Value length must be greater than %d",
 			Args:    []interface{}{int(validator.Length)},
@@ -166,13 +284,18 @@ func (validator *MinLengthValidator) IsValid() bool {
 	return true
 }
 
+func (validator *MinLengthValidator) Constraints() []*Attribute {
+	return []*Attribute{{Key: "minlength", Value: strconv.Itoa(validator.Length)}}
+}
+
+// MaxLengthValidator counts the characters of the value, not its bytes.
 type MaxLengthValidator struct {
 	Length int
 	Validator
 }
 
 func (validator *MaxLengthValidator) IsValid() bool {
-	if len(validator.Value) > validator.Length {
+	if utf8.RuneCountInString(validator.Value) > validator.Length {
 		validator.Messages = append(validator.Messages, Message{
 			Message: "Value length must be lower than %d",
 			Args:    []interface{}{int(validator.Length)},
@@ -182,6 +305,52 @@ func (validator *MaxLengthValidator) IsValid() bool {
 	return true
 }
 
+func (validator *MaxLengthValidator) Constraints() []*Attribute {
+	return []*Attribute{{Key: "maxlength", Value: strconv.Itoa(validator.Length)}}
+}
+
+// RegexValidator matches the whole value against Pattern, the same way the
+// browser applies the pattern attribute. Empty values are left to the
+// RequiredValidator.
+type RegexValidator struct {
+	Pattern string
+	Validator
+	compiled *regexp.Regexp
+	// compiledPattern is the Pattern compiled was built from
+	compiledPattern string
+}
+
+// compile returns the anchored Pattern, it is compiled again only when Pattern
+// changes.
+func (validator *RegexValidator) compile() (*regexp.Regexp, error) {
+	if validator.compiled == nil || validator.compiledPattern != validator.Pattern {
+		compiled, err := regexp.Compile("^(?:" + validator.Pattern + ")$")
+		if err != nil {
+			return nil, err
+		}
+		validator.compiled, validator.compiledPattern = compiled, validator.Pattern
+	}
+	return validator.compiled, nil
+}
+
+func (validator *RegexValidator) IsValid() bool {
+	if validator.Value == "" {
+		return true
+	}
+	compiled, err := validator.compile()
+	if err != nil || !compiled.MatchString(validator.Value) {
+		validator.Messages = append(validator.Messages, Message{
+			Message: "Value does not match the required format",
+		})
+		return false
+	}
+	return true
+}
+
+func (validator *RegexValidator) Constraints() []*Attribute {
+	return []*Attribute{{Key: "pattern", Value: validator.Pattern}}
+}
+
 type EmailAddressValidator struct {
 	WithHost bool
 	Validator
@@ -214,6 +383,13 @@ type IdenticalValidator struct {
 }
 
 func (validator *IdenticalValidator) IsValid() bool {
+	if validator.element == nil {
+		validator.Messages = append(validator.Messages, Message{
+			Message: "Values does not matched with %s",
+			Args:    []interface{}{validator.ElementName},
+		})
+		return false
+	}
 	if validator.Value != validator.element.GetValue() {
 		validator.Messages = append(validator.Messages, Message{
 			Message: "Values does not matched with %s",
@@ -223,3 +399,285 @@ func (validator *IdenticalValidator) IsValid() bool {
 	}
 	return true
 }
+
+// CaptchaValidator verifies the response bound to a captcha element with its
+// provider, NewCaptchaElement attaches it.
+type CaptchaValidator struct {
+	element *CaptchaElement
+	Validator
+}
+
+func (validator *CaptchaValidator) IsValid() bool {
+	ok, err := validator.element.Provider.Verify(validator.element.Name, validator.element.GetResponse())
+	if err != nil {
+		validator.Messages = append(validator.Messages, Message{
+			Message: "Captcha could not be verified",
+		})
+		return false
+	}
+	if !ok {
+		validator.Messages = append(validator.Messages, Message{
+			Message: "Captcha answer is not correct",
+		})
+		return false
+	}
+	return true
+}
+
+func (validator *CaptchaValidator) Constraints() []*Attribute {
+	return []*Attribute{{Key: "required", Value: "required"}}
+}
+
+// FilesValidator is implemented by validators checking all files of a multi
+// file element at once, such as the number of files. Other validators are run
+// for every file of a multi file element.
+type FilesValidator interface {
+	IsValidFiles(files []*File) bool
+}
+
+// singleFile returns the file of a single file element as a list of files.
+func (validator *Validator) singleFile() []*File {
+	if validator.File == nil {
+		return nil
+	}
+	return []*File{validator.File}
+}
+
+type MinFilesValidator struct {
+	Min int
+	Validator
+}
+
+func (validator *MinFilesValidator) IsValid() bool {
+	return validator.IsValidFiles(validator.singleFile())
+}
+
+func (validator *MinFilesValidator) IsValidFiles(files []*File) bool {
+	if len(files) < validator.Min {
+		validator.Messages = append(validator.Messages, Message{
+			Message: "At least %d files are required",
+			Args:    []interface{}{validator.Min},
+		})
+		return false
+	}
+	return true
+}
+
+type MaxFilesValidator struct {
+	Max int
+	Validator
+}
+
+func (validator *MaxFilesValidator) IsValid() bool {
+	return validator.IsValidFiles(validator.singleFile())
+}
+
+func (validator *MaxFilesValidator) IsValidFiles(files []*File) bool {
+	if len(files) > validator.Max {
+		validator.Messages = append(validator.Messages, Message{
+			Message: "At most %d files are allowed",
+			Args:    []interface{}{validator.Max},
+		})
+		return false
+	}
+	return true
+}
+
+// MaxTotalSizeValidator limits the size of all files together, in bytes.
+type MaxTotalSizeValidator struct {
+	Max int64
+	Validator
+}
+
+func (validator *MaxTotalSizeValidator) IsValid() bool {
+	return validator.IsValidFiles(validator.singleFile())
+}
+
+func (validator *MaxTotalSizeValidator) IsValidFiles(files []*File) bool {
+	var total int64
+	for _, file := range files {
+		total += file.Size
+	}
+	if total > validator.Max {
+		validator.Messages = append(validator.Messages, Message{
+			Message: "Files must be smaller than %d bytes in total",
+			Args:    []interface{}{validator.Max},
+		})
+		return false
+	}
+	return true
+}
+
+// MaxFileSizeValidator limits the size of a file, in bytes.
+type MaxFileSizeValidator struct {
+	Max int64
+	Validator
+}
+
+func (validator *MaxFileSizeValidator) IsValid() bool {
+	if validator.File != nil && validator.File.Size > validator.Max {
+		validator.Messages = append(validator.Messages, Message{
+			Message: "File %s must be smaller than %d bytes",
+			Args:    []interface{}{validator.File.Name, validator.Max},
+		})
+		return false
+	}
+	return true
+}
+
+// FileExtensionValidator accepts files with one of Extensions, given without
+// the dot, e.g. "jpg". The comparison ignores case.
+type FileExtensionValidator struct {
+	Extensions []string
+	Validator
+}
+
+func (validator *FileExtensionValidator) IsValid() bool {
+	if validator.File == nil {
+		return true
+	}
+	for _, extension := range validator.Extensions {
+		if strings.EqualFold(extension, validator.File.Extension) {
+			return true
+		}
+	}
+	validator.Messages = append(validator.Messages, Message{
+		Message: "File %s must have one of the extensions %s",
+		Args:    []interface{}{validator.File.Name, strings.Join(validator.Extensions, ", ")},
+	})
+	return false
+}
+
+func (validator *FileExtensionValidator) Constraints() []*Attribute {
+	var accept []string
+	for _, extension := range validator.Extensions {
+		accept = append(accept, "."+strings.ToLower(extension))
+	}
+	return []*Attribute{{Key: "accept", Value: strings.Join(accept, ",")}}
+}
+
+// FormValidator validates the form as a whole, for rules involving several
+// elements. It reports errors on elements with AddError or on the form with
+// AddFormError.
+type FormValidator interface {
+	IsValid(form *Form) bool
+}
+
+// FormValidatorFunc adapts a function to a FormValidator.
+type FormValidatorFunc func(form *Form) bool
+
+func (f FormValidatorFunc) IsValid(form *Form) bool {
+	return f(form)
+}
+
+// formValidatorElements returns the named elements, a missing element is
+// reported as a form error.
+func formValidatorElements(form *Form, names []string) ([]ElementInterface, bool) {
+	var elements []ElementInterface
+	for _, name := range names {
+		element, err := form.Get(name)
+		if err != nil {
+			form.AddFormError("Element %s not found", []interface{}{name})
+			return nil, false
+		}
+		elements = append(elements, element)
+	}
+	return elements, true
+}
+
+func joinLabels(elements []ElementInterface) string {
+	var labels []string
+	for _, element := range elements {
+		labels = append(labels, element.GetLabel())
+	}
+	return strings.Join(labels, ", ")
+}
+
+// DateRangeValidator requires the End element to be after the Start element.
+// Both are parsed with the layout of date and time elements, empty values are
+// left to the RequiredValidator. The error is reported on the End element.
+type DateRangeValidator struct {
+	Start string
+	End   string
+}
+
+func (validator *DateRangeValidator) IsValid(form *Form) bool {
+	elements, ok := formValidatorElements(form, []string{validator.Start, validator.End})
+	if !ok {
+		return false
+	}
+	start, end := elements[0], elements[1]
+	if start.GetValue() == "" || end.GetValue() == "" {
+		return true
+	}
+	startTime, err := elementTime(start, start.GetValue())
+	if err != nil {
+		return true
+	}
+	endTime, err := elementTime(end, end.GetValue())
+	if err != nil {
+		return true
+	}
+	if !endTime.After(startTime) {
+		end.AddError("Value must be after than %s", []interface{}{start.GetLabel()})
+		return false
+	}
+	return true
+}
+
+// AtLeastOneValidator requires at least one of the Elements to have a value,
+// e.g. a phone number or an email address. The error is a form error.
+type AtLeastOneValidator struct {
+	Elements []string
+}
+
+func (validator *AtLeastOneValidator) IsValid(form *Form) bool {
+	elements, ok := formValidatorElements(form, validator.Elements)
+	if !ok {
+		return false
+	}
+	for _, element := range elements {
+		if element.GetValue() != "" || len(element.GetValues()) > 0 || element.GetFile() != nil {
+			return true
+		}
+	}
+	form.AddFormError("At least one of %s is required", []interface{}{joinLabels(elements)})
+	return false
+}
+
+// SumValidator requires the numeric values of the Elements to add up to Sum,
+// e.g. percentages adding up to 100. Empty values count as 0. The error is a
+// form error, values which are not numbers are reported on their elements.
+type SumValidator struct {
+	Elements []string
+	Sum      float64
+}
+
+func (validator *SumValidator) IsValid(form *Form) bool {
+	elements, ok := formValidatorElements(form, validator.Elements)
+	if !ok {
+		return false
+	}
+	valid := true
+	var sum float64
+	for _, element := range elements {
+		if element.GetValue() == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(element.GetValue(), 64)
+		if err != nil {
+			valid = false
+			element.AddError("Value must be a number", nil)
+			continue
+		}
+		sum += value
+	}
+	if !valid {
+		return false
+	}
+	if math.Abs(sum-validator.Sum) > 1e-9 {
+		form.AddFormError("The sum of %s must be %s", []interface{}{joinLabels(elements), strconv.FormatFloat(validator.Sum, 'f', -1, 64)})
+		return false
+	}
+	return true
+}
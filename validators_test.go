@@ -0,0 +1,170 @@
+package goform
+
+import (
+	"bytes"
+	"mime/multipart"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestLengthValidatorsCountCharacters(t *testing.T) {
+	tests := []struct {
+		value string
+		min   bool
+		max   bool
+	}{
+		{"abcde", true, true},
+		{"çğışöü", true, false},
+		{"şöü", false, true},
+		{"日本語です", true, true},
+		{"👍👍👍👍👍", true, true},
+	}
+	for _, test := range tests {
+		min := &MinLengthValidator{Length: 4}
+		min.SetValue(test.value)
+		if got := min.IsValid(); got != test.min {
+			t.Errorf("MinLengthValidator{4}.IsValid(%q) = %v, want %v", test.value, got, test.min)
+		}
+		max := &MaxLengthValidator{Length: 5}
+		max.SetValue(test.value)
+		if got := max.IsValid(); got != test.max {
+			t.Errorf("MaxLengthValidator{5}.IsValid(%q) = %v, want %v", test.value, got, test.max)
+		}
+	}
+}
+
+func TestRegexValidatorCompilesOnce(t *testing.T) {
+	validator := &RegexValidator{Pattern: "[0-9]+"}
+	validator.SetValue("123")
+	if !validator.IsValid() {
+		t.Fatal("IsValid() = false, want true")
+	}
+	compiled := validator.compiled
+	if !validator.IsValid() || validator.compiled != compiled {
+		t.Error("the pattern was compiled again")
+	}
+
+	validator.Pattern = "[a-z]+"
+	if validator.IsValid() {
+		t.Error("IsValid() = true after changing the pattern, want false")
+	}
+
+	validator.Pattern = "[0-9"
+	validator.SetValue("1")
+	if validator.IsValid() {
+		t.Error("IsValid() = true with an invalid pattern, want false")
+	}
+}
+
+func TestMultiFileValidation(t *testing.T) {
+	files := func(sizes map[string]int) []*multipart.FileHeader {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		for name, size := range sizes {
+			part, _ := writer.CreateFormFile("docs", name)
+			part.Write(bytes.Repeat([]byte("x"), size))
+		}
+		writer.Close()
+		reader := multipart.NewReader(&body, writer.Boundary())
+		form, err := reader.ReadForm(1 << 20)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return form.File["docs"]
+	}
+	tests := []struct {
+		name       string
+		validators func() []ValidatorInterface
+		files      map[string]int
+		want       []string
+	}{
+		{
+			name:       "per file",
+			validators: perFileValidators,
+			files:      map[string]int{"a.pdf": 50, "b.pdf": 200, "c.exe": 10, "d.PDF": 300},
+			want: []string{
+				"File b.pdf must be smaller than 100 bytes",
+				"File d.PDF must be smaller than 100 bytes",
+				"File c.exe must have one of the extensions pdf",
+			},
+		},
+		{
+			name:       "per file without files",
+			validators: perFileValidators,
+		},
+		{
+			name:       "all files",
+			validators: allFilesValidators,
+			files:      map[string]int{"a.pdf": 50, "b.pdf": 50},
+		},
+		{
+			name:       "too many files",
+			validators: allFilesValidators,
+			files:      map[string]int{"a.pdf": 50, "b.pdf": 50, "c.pdf": 1},
+			want:       []string{"At most 2 files are allowed", "Files must be smaller than 100 bytes in total"},
+		},
+		{
+			name:       "too few files",
+			validators: allFilesValidators,
+			want:       []string{"At least 1 files are required"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			form := NewGoForm()
+			docs := NewMultiFileElement("docs", "Docs", nil, test.validators(), nil)
+			form.Add(docs)
+			form.bindFiles(map[string][]*multipart.FileHeader{"docs": files(test.files)})
+			if len(docs.Files) != len(test.files) {
+				t.Fatalf("bound %d files, want %d", len(docs.Files), len(test.files))
+			}
+
+			// validating twice reports every error once
+			for i := 0; i < 2; i++ {
+				if valid := docs.IsValid(); valid != (len(test.want) == 0) {
+					t.Errorf("IsValid() = %v, errors %v", valid, docs.GetErrorMessages())
+				}
+			}
+			messages := docs.GetErrorMessages()
+			if len(test.want) == 0 {
+				return
+			}
+			sort.Strings(messages)
+			want := append([]string(nil), test.want...)
+			sort.Strings(want)
+			if strings.Join(messages, "\n") != strings.Join(want, "\n") {
+				t.Errorf("errors = %q, want %q", messages, want)
+			}
+		})
+	}
+}
+
+func perFileValidators() []ValidatorInterface {
+	return []ValidatorInterface{&MaxFileSizeValidator{Max: 100}, &FileExtensionValidator{Extensions: []string{"pdf"}}}
+}
+
+func allFilesValidators() []ValidatorInterface {
+	return []ValidatorInterface{&MinFilesValidator{Min: 1}, &MaxFilesValidator{Max: 2}, &MaxTotalSizeValidator{Max: 100}}
+}
+
+func TestFilesValidatorSingleFile(t *testing.T) {
+	tests := []struct {
+		validator ValidatorInterface
+		file      *File
+		valid     bool
+	}{
+		{&MinFilesValidator{Min: 1}, nil, false},
+		{&MinFilesValidator{Min: 1}, &File{Name: "a.pdf"}, true},
+		{&MaxFilesValidator{Max: 0}, &File{Name: "a.pdf"}, false},
+		{&MaxTotalSizeValidator{Max: 10}, &File{Name: "a.pdf", Size: 11}, false},
+		{&MaxTotalSizeValidator{Max: 10}, nil, true},
+	}
+	for i, test := range tests {
+		element := NewFileElement("doc", "Doc", nil, []ValidatorInterface{test.validator}, nil, "")
+		element.SetFile(test.file)
+		if valid := element.IsValid(); valid != test.valid {
+			t.Errorf("%d: IsValid() = %v, want %v", i, valid, test.valid)
+		}
+	}
+}